@@ -0,0 +1,45 @@
+package jorm
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// association adapts a *gorm.Association to the Association interface
+type association struct {
+	a *gorm.Association
+}
+
+// Find find out all related associations
+func (a *association) Find(value interface{}) Association {
+	return &association{a: a.a.Find(value)}
+}
+
+// Append append new associations for many2many, has_many, replace current association for has_one, belongs_to
+func (a *association) Append(values ...interface{}) Association {
+	return &association{a: a.a.Append(values...)}
+}
+
+// Replace replace current associations with new one
+func (a *association) Replace(values ...interface{}) Association {
+	return &association{a: a.a.Replace(values...)}
+}
+
+// Delete remove relationship between source & passed arguments, but won't delete those arguments
+func (a *association) Delete(values ...interface{}) Association {
+	return &association{a: a.a.Delete(values...)}
+}
+
+// Clear remove relationship between source & current associations, won't delete those associations
+func (a *association) Clear() Association {
+	return &association{a: a.a.Clear()}
+}
+
+// Count return the count of current associations
+func (a *association) Count() int {
+	return a.a.Count()
+}
+
+// Error returns the error of the last association operation
+func (a *association) Error() error {
+	return a.a.Error
+}
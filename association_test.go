@@ -0,0 +1,109 @@
+package jorm_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/mocks"
+)
+
+type member struct {
+	ID    uint
+	Roles []role `gorm:"many2many:member_roles"`
+}
+
+type role struct {
+	ID   uint
+	Name string
+}
+
+func TestAssociation(t *testing.T) {
+	db := open(t)
+	if err := db.AutoMigrate(&member{}, &role{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	m := member{}
+	if err := db.Create(&m).Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	roles := db.Model(&m).Association("Roles")
+	if err := roles.Append(&role{Name: "admin"}, &role{Name: "editor"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Model(&m).Association("Roles").Count(); n != 2 {
+		t.Errorf("Count = %d", n)
+	}
+	var found []role
+	if err := db.Model(&m).Association("Roles").Find(&found).Error(); err != nil || len(found) != 2 {
+		t.Errorf("Find = %v %v", found, err)
+	}
+	if err := db.Model(&m).Association("Roles").Delete(&found[0]).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&m).Association("Roles").Replace(&role{Name: "viewer"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	found = nil
+	if err := db.Model(&m).Association("Roles").Find(&found).Error(); err != nil || len(found) != 1 || found[0].Name != "viewer" {
+		t.Errorf("Find after Replace = %v %v", found, err)
+	}
+	if err := db.Model(&m).Association("Roles").Clear().Error(); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Model(&m).Association("Roles").Count(); n != 0 {
+		t.Errorf("Count after Clear = %d", n)
+	}
+	if err := db.Model(&m).Association("Missing").Error(); err == nil {
+		t.Error("Association of an unknown field has no error")
+	}
+}
+
+func TestCallbackDialectAndCommonDB(t *testing.T) {
+	db := open(t)
+
+	created := 0
+	db.Callback().Create().After("gorm:create").Register("jorm_test:count", func(*gorm.Scope) { created++ })
+	t.Cleanup(func() { db.Callback().Create().Remove("jorm_test:count") })
+	if db.Callback().Create().Get("jorm_test:count") == nil {
+		t.Error("Get of a registered callback = nil")
+	}
+	if err := db.Create(&user{Name: "alice"}).Error(); err != nil || created != 1 {
+		t.Errorf("Create ran the callback %d times, %v", created, err)
+	}
+
+	if name := db.Dialect().GetName(); name != "sqlite3" {
+		t.Errorf("Dialect().GetName() = %q", name)
+	}
+	if !db.Dialect().HasTable("users") {
+		t.Error("Dialect().HasTable(users) = false")
+	}
+	var n int
+	if err := db.CommonDB().QueryRow("SELECT count(*) FROM users").Scan(&n); err != nil || n != 1 {
+		t.Errorf("CommonDB().QueryRow = %d %v", n, err)
+	}
+}
+
+// addRole appends a role through association mode
+func addRole(db jorm.Interface, m *member, name string) error {
+	return db.Model(m).Association("Roles").Append(&role{Name: name}).Error()
+}
+
+func TestAssociationWithMocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := mocks.NewChainMock(ctrl)
+	association := mocks.NewMockAssociation(ctrl)
+	m := &member{ID: 1}
+	db.EXPECT().Association("Roles").Return(association)
+	association.EXPECT().Append(&role{Name: "admin"}).Return(association)
+	association.EXPECT().Error().Return(errBoom)
+
+	if err := addRole(db, m, "admin"); err != errBoom {
+		t.Errorf("addRole = %v", err)
+	}
+	db.AssertCalled(t, "Model", m)
+}
@@ -0,0 +1,76 @@
+package jorm
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// callback adapts a *gorm.Callback to the Callback interface
+type callback struct {
+	c *gorm.Callback
+}
+
+// Create could be used to register callbacks for creating object
+func (c *callback) Create() CallbackProcessor {
+	return &callbackProcessor{p: c.c.Create()}
+}
+
+// Update could be used to register callbacks for updating object, refer `Create` for usage
+func (c *callback) Update() CallbackProcessor {
+	return &callbackProcessor{p: c.c.Update()}
+}
+
+// Delete could be used to register callbacks for deleting object, refer `Create` for usage
+func (c *callback) Delete() CallbackProcessor {
+	return &callbackProcessor{p: c.c.Delete()}
+}
+
+// Query could be used to register callbacks for querying objects with query methods like `Find`, `First`, `Related`, `Association`...
+func (c *callback) Query() CallbackProcessor {
+	return &callbackProcessor{p: c.c.Query()}
+}
+
+// RowQuery could be used to register callbacks for querying objects with `Row`, `Rows`, refer `Create` for usage
+func (c *callback) RowQuery() CallbackProcessor {
+	return &callbackProcessor{p: c.c.RowQuery()}
+}
+
+// callbackProcessor adapts a *gorm.CallbackProcessor to the CallbackProcessor interface
+type callbackProcessor struct {
+	p *gorm.CallbackProcessor
+}
+
+// After insert a new callback after callback `callbackName`, refer `Callbacks.Create`
+func (cp *callbackProcessor) After(callbackName string) CallbackProcessor {
+	return &callbackProcessor{p: cp.p.After(callbackName)}
+}
+
+// Before insert a new callback before callback `callbackName`, refer `Callbacks.Create`
+func (cp *callbackProcessor) Before(callbackName string) CallbackProcessor {
+	return &callbackProcessor{p: cp.p.Before(callbackName)}
+}
+
+// Register a new callback, refer `Callbacks.Create`
+func (cp *callbackProcessor) Register(callbackName string, callback func(scope *gorm.Scope)) {
+	cp.p.Register(callbackName, callback)
+}
+
+// Remove a registered callback
+//     db.Callback().Create().Remove("gorm:update_time_stamp_when_create")
+func (cp *callbackProcessor) Remove(callbackName string) {
+	cp.p.Remove(callbackName)
+}
+
+// Replace a registered callback with new callback
+//     db.Callback().Create().Replace("gorm:update_time_stamp_when_create", func(*Scope) {
+//		   scope.SetColumn("Created", now)
+//		   scope.SetColumn("Updated", now)
+//     })
+func (cp *callbackProcessor) Replace(callbackName string, callback func(scope *gorm.Scope)) {
+	cp.p.Replace(callbackName, callback)
+}
+
+// Get registered callback
+//    db.Callback().Create().Get("gorm:create")
+func (cp *callbackProcessor) Get(callbackName string) func(scope *gorm.Scope) {
+	return cp.p.Get(callbackName)
+}
@@ -0,0 +1,137 @@
+package jorm
+
+import (
+	"database/sql"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/jinzhu/gorm"
+)
+
+// replicaSetting is the gorm setting the replica a read runs on is stored under
+const replicaSetting = "jorm:replica"
+
+// selectRegexp matches the statements Raw can send to a replica
+var selectRegexp = regexp.MustCompile(`(?i)^\s*(\(\s*)*select\b`)
+
+// ReplicaPolicy chooses the replica a read runs on, replicas is never empty
+type ReplicaPolicy func(replicas []*sql.DB) *sql.DB
+
+// RoundRobin returns a policy that takes turns between the replicas
+func RoundRobin() ReplicaPolicy {
+	var next uint32
+	return func(replicas []*sql.DB) *sql.DB {
+		n := atomic.AddUint32(&next, 1) - 1
+		return replicas[n%uint32(len(replicas))]
+	}
+}
+
+// LeastConnections returns a policy that chooses the replica with the fewest connections in use
+func LeastConnections() ReplicaPolicy {
+	return func(replicas []*sql.DB) *sql.DB {
+		least := replicas[0]
+		inUse := least.Stats().InUse
+		for _, replica := range replicas[1:] {
+			if n := replica.Stats().InUse; n < inUse {
+				least, inUse = replica, n
+			}
+		}
+		return least
+	}
+}
+
+// cluster are the replicas of a db, the policy choosing between them and how reads keep up with writes
+type cluster struct {
+	primary     *sql.DB
+	replicas    []*sql.DB
+	policy      ReplicaPolicy
+	monitor     *lagMonitor
+	consistency *ReadYourWritesOptions
+}
+
+// NewClusterDB returns a new interface wrapper around the primary that sends reads to the connection pools of the
+// replicas, round-robin unless WithReplicaPolicy changes it. First, Take, Last, Find, Scan, Count, Pluck, Row and
+// Rows, and Raw SELECTs, run on a replica while every other statement and everything in a transaction run on the
+// primary. UsePrimary sends the reads of a call chain to the primary, for example to read what it just wrote
+//     db := jorm.NewClusterDB(primary, replica1, replica2)
+//     db.Where("email = ?", email).First(&user)              // runs on a replica
+//     db.UsePrimary().Where("email = ?", email).First(&user) // runs on the primary
+func NewClusterDB(primary *gorm.DB, replicas ...*gorm.DB) *DB {
+	db := NewDB(primary)
+	if len(replicas) == 0 {
+		return db
+	}
+
+	db.cluster = &cluster{primary: primary.DB(), policy: RoundRobin()}
+	for _, replica := range replicas {
+		db.cluster.replicas = append(db.cluster.replicas, replica.DB())
+	}
+	return db
+}
+
+// WithReplicaPolicy returns a clone of the db that chooses the replica of each read with the policy
+//     db = db.WithReplicaPolicy(jorm.LeastConnections())
+func (db *DB) WithReplicaPolicy(policy ReplicaPolicy) *DB {
+	c := db.clone(db.db)
+	if db.cluster != nil {
+		cl := *db.cluster
+		cl.policy = policy
+		c.cluster = &cl
+	}
+	return c
+}
+
+// UsePrimary returns a clone of the db whose reads run on the primary, it does nothing to a db without replicas
+func (db *DB) UsePrimary() Interface {
+	c := db.clone(db.db)
+	c.usePrimary = true
+	return c
+}
+
+// replica returns the replica the next read of the db runs on, nil if it runs on the primary
+func (db *DB) replica() *sql.DB {
+	if db.cluster == nil || db.usePrimary || db.txDepth > 0 {
+		return nil
+	}
+
+	replicas := db.cluster.inRotation()
+	if len(replicas) == 0 {
+		return nil
+	}
+	if w := writesOf(db.ctx); w != nil && db.cluster.consistency != nil {
+		return db.cluster.caughtUpReplica(db.ctx, w, replicas)
+	}
+	return db.cluster.policy(replicas)
+}
+
+// inRotation returns the replicas reads can run on
+func (c *cluster) inRotation() []*sql.DB {
+	if c.monitor == nil {
+		return c.replicas
+	}
+	return c.monitor.inRotation()
+}
+
+// read runs a read on a replica, or on the primary if the db should, the returned db is back on the primary
+func (db *DB) read(fn func(gormDB *gorm.DB) *gorm.DB) *DB {
+	replica := db.replicaDB()
+	if replica == db.db {
+		return db.clone(fn(db.db))
+	}
+
+	c := db.clone(fn(replica).Set(replicaSetting, nil))
+	setCommonDB(c.db, db.db.CommonDB())
+	return c
+}
+
+// replicaDB returns a clone of the gorm db that runs on a replica, or the gorm db itself if it runs on the primary
+func (db *DB) replicaDB() *gorm.DB {
+	replica := db.replica()
+	if replica == nil {
+		return db.db
+	}
+
+	c := db.clone(db.db.Set(replicaSetting, replica))
+	setCommonDB(c.db, replica)
+	return c.withContextConn().db
+}
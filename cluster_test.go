@@ -0,0 +1,133 @@
+package jorm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+)
+
+// openCluster returns a cluster of a primary and two replicas, each a SQLite database whose only user is named
+// after it
+func openCluster(t *testing.T) *jorm.DB {
+	t.Helper()
+	dir := t.TempDir()
+	dbs := make([]*gorm.DB, 3)
+	for i, name := range []string{"primary", "replica1", "replica2"} {
+		g, err := gorm.Open("sqlite3", filepath.Join(dir, name+".db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { g.Close() })
+		if err := g.AutoMigrate(&user{}).Create(&user{Name: name}).Error; err != nil {
+			t.Fatal(err)
+		}
+		dbs[i] = g
+	}
+	return jorm.NewClusterDB(dbs[0], dbs[1:]...)
+}
+
+// firstName returns the name of the first user the db finds
+func firstName(t *testing.T, db jorm.Interface) string {
+	t.Helper()
+	var u user
+	if err := db.First(&u).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return u.Name
+}
+
+func TestClusterReadsRunOnReplicas(t *testing.T) {
+	db := openCluster(t)
+
+	if a, b, c := firstName(t, db), firstName(t, db), firstName(t, db); a != "replica1" || b != "replica2" || c != "replica1" {
+		t.Errorf("round-robin reads ran on %s, %s and %s", a, b, c)
+	}
+	var names []string
+	if err := db.Raw("SELECT name FROM users").Pluck("name", &names).Error(); err != nil || len(names) != 1 || names[0] == "primary" {
+		t.Errorf("Raw SELECT = %v %v", names, err)
+	}
+	var count int
+	if err := db.Model(&user{}).Where("name = ?", "primary").Count(&count).Error(); err != nil || count != 0 {
+		t.Errorf("Count = %d %v", count, err)
+	}
+}
+
+func TestClusterUsePrimary(t *testing.T) {
+	db := openCluster(t)
+
+	if name := firstName(t, db.UsePrimary()); name != "primary" {
+		t.Errorf("UsePrimary read ran on %s", name)
+	}
+	if name := firstName(t, db.WithContext(context.Background()).UsePrimary().Where("id = ?", 1)); name != "primary" {
+		t.Errorf("UsePrimary read of a call chain ran on %s", name)
+	}
+}
+
+func TestClusterWritesRunOnPrimary(t *testing.T) {
+	db := openCluster(t)
+
+	if err := db.Model(&user{}).Where("id = ?", 1).Update("name", "changed").Error(); err != nil {
+		t.Fatal(err)
+	}
+	// a Raw statement that is not a SELECT runs on the primary
+	if err := db.Exec("INSERT INTO users (name) VALUES ('exec')").Error(); err != nil {
+		t.Fatal(err)
+	}
+	// the update returns no record to scan
+	if err := db.Raw("UPDATE users SET name = 'raw' WHERE name = 'exec'").Scan(&user{}).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Fatal(err)
+	}
+	if names := names(t, db.UsePrimary()); fmt.Sprint(names) != "[changed raw]" {
+		t.Errorf("primary names = %v", names)
+	}
+	if names := names(t, db); fmt.Sprint(names) != "[replica1]" {
+		t.Errorf("replica names = %v", names)
+	}
+}
+
+func TestClusterTransactionRunsOnPrimary(t *testing.T) {
+	db := openCluster(t)
+
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		if name := firstName(t, tx); name != "primary" {
+			t.Errorf("read in a transaction ran on %s", name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClusterReplicaPolicy(t *testing.T) {
+	db := openCluster(t).WithReplicaPolicy(func(replicas []*sql.DB) *sql.DB {
+		return replicas[len(replicas)-1]
+	})
+	if a, b := firstName(t, db), firstName(t, db); a != "replica2" || b != "replica2" {
+		t.Errorf("reads ran on %s and %s", a, b)
+	}
+
+	db = db.WithReplicaPolicy(jorm.LeastConnections())
+	if name := firstName(t, db); name != "replica1" {
+		t.Errorf("LeastConnections read ran on %s", name)
+	}
+}
+
+func TestClusterClose(t *testing.T) {
+	db := openCluster(t)
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UsePrimary().First(&user{}).Error(); err == nil {
+		t.Error("read of a closed primary has no error")
+	}
+	if err := db.First(&user{}).Error(); err == nil {
+		t.Error("read of a closed replica has no error")
+	}
+}
@@ -0,0 +1,123 @@
+package jorm
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"unsafe"
+
+	"github.com/jinzhu/gorm"
+)
+
+// gorm settings the context given to WithContext and the connection given to WithConnection are stored under
+const (
+	contextSetting    = "jorm:context"
+	connectionSetting = "jorm:connection"
+)
+
+// sqlCommonContext is implemented by *sql.DB, *sql.Tx and *sql.Conn
+type sqlCommonContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// contextConn is a gorm.SQLCommon that runs every statement with a context
+type contextConn struct {
+	ctx  context.Context
+	conn sqlCommonContext
+}
+
+// Exec executes a statement with the context
+func (c *contextConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.conn.ExecContext(c.ctx, query, args...)
+}
+
+// Prepare prepares a statement with the context
+func (c *contextConn) Prepare(query string) (*sql.Stmt, error) {
+	return c.conn.PrepareContext(c.ctx, query)
+}
+
+// Query executes a query with the context
+func (c *contextConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.conn.QueryContext(c.ctx, query, args...)
+}
+
+// QueryRow executes a query that returns at most one row with the context
+func (c *contextConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.conn.QueryRowContext(c.ctx, query, args...)
+}
+
+// contextDB is a contextConn around a *sql.DB which also begins transactions with the context
+type contextDB struct {
+	contextConn
+	db *sql.DB
+}
+
+// Begin starts a transaction with the context
+func (c *contextDB) Begin() (*sql.Tx, error) {
+	return c.db.BeginTx(c.ctx, nil)
+}
+
+// BeginTx starts a transaction with the context, gorm always passes a background context so it is ignored
+func (c *contextDB) BeginTx(_ context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(c.ctx, opts)
+}
+
+// contextTx is a contextConn around a *sql.Tx which refuses to commit once the context is done
+type contextTx struct {
+	contextConn
+	tx *sql.Tx
+}
+
+// Commit commits the transaction, or rolls it back and returns the context's error if the context is done
+func (c *contextTx) Commit() error {
+	if err := c.ctx.Err(); err != nil {
+		c.tx.Rollback()
+		return err
+	}
+	return c.tx.Commit()
+}
+
+// Rollback rolls back the transaction
+func (c *contextTx) Rollback() error {
+	return c.tx.Rollback()
+}
+
+// withContextConn wraps the connection in a contextConn for the given context, connections that do not support
+// contexts are returned as is
+func withContextConn(ctx context.Context, conn gorm.SQLCommon) gorm.SQLCommon {
+	switch c := unwrapConn(conn).(type) {
+	case *sql.DB:
+		return &contextDB{contextConn: contextConn{ctx: ctx, conn: c}, db: c}
+	case *sql.Tx:
+		if c == nil {
+			return conn
+		}
+		return &contextTx{contextConn: contextConn{ctx: ctx, conn: c}, tx: c}
+	case sqlCommonContext:
+		return &contextConn{ctx: ctx, conn: c}
+	}
+	return conn
+}
+
+// unwrapConn returns the connection a contextConn was created around
+func unwrapConn(conn gorm.SQLCommon) gorm.SQLCommon {
+	switch c := conn.(type) {
+	case *contextDB:
+		return c.db
+	case *contextTx:
+		return c.tx
+	case *contextConn:
+		return c.conn.(gorm.SQLCommon)
+	}
+	return conn
+}
+
+// setCommonDB replaces the connection of the given *gorm.DB, gorm only ever changes it in Begin
+// so the unexported field has to be set directly
+func setCommonDB(db *gorm.DB, conn gorm.SQLCommon) {
+	field := reflect.ValueOf(db).Elem().FieldByName("db")
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(&conn).Elem())
+}
@@ -0,0 +1,63 @@
+package jorm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jloom6/jorm"
+)
+
+func TestWithContextCancelled(t *testing.T) {
+	db := open(t)
+	if err := db.Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var users []user
+	if err := db.Where("name = ?", "alice").WithContext(ctx).Find(&users).Error(); err != context.Canceled {
+		t.Errorf("Find with a cancelled context = %v", err)
+	}
+	if err := db.WithContext(ctx).Create(&user{Name: "bob"}).Error(); err != context.Canceled {
+		t.Errorf("Create with a cancelled context = %v", err)
+	}
+	if err := db.WithContext(ctx).Transaction(ctx, func(tx jorm.Interface) error { return nil }); err != context.Canceled {
+		t.Errorf("Transaction with a cancelled context = %v", err)
+	}
+}
+
+func TestWithContextDeadline(t *testing.T) {
+	db := open(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if err := db.WithContext(ctx).Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatalf("Create before the deadline = %v", err)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	var users []user
+	if err := db.WithContext(ctx).Find(&users).Error(); err != context.DeadlineExceeded {
+		t.Errorf("Find after the deadline = %v", err)
+	}
+}
+
+func TestWithContextCommitAfterCancel(t *testing.T) {
+	db := open(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tx := db.WithContext(ctx).Begin()
+	if err := tx.Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := tx.Commit().Error(); err != context.Canceled {
+		t.Errorf("Commit after cancel = %v", err)
+	}
+	if got := names(t, db); len(got) != 0 {
+		t.Errorf("users = %v, the transaction must be rolled back", got)
+	}
+}
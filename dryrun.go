@@ -0,0 +1,208 @@
+package jorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// gorm setting of dry run dbs and name the statement hook of ToSQL is added under
+const (
+	dryRunSetting = "jorm:dry_run"
+	toSQLHookName = "jorm:to_sql"
+)
+
+// ErrNoStatement is returned by ToSQL when the function given to it neither runs nor builds a statement
+var ErrNoStatement = errors.New("jorm: no statement")
+
+// dryRunDB is the connection of dry run dbs, its statements never reach a database
+var dryRunDB = sql.OpenDB(dryRunConnector{})
+
+// DryRun returns a clone of the db whose statements are built by the gorm callbacks and passed to the statement
+// hooks but never sent to the database. Queries find no records and other statements change no rows, so First
+// and Count return errors like they would for an empty table. Migrations and the dialect's own queries are not
+// dry and still use the database
+//     db.DryRun().OnStatement("print", func(stmt *jorm.Statement) {
+//         fmt.Println(stmt.SQL, stmt.Vars)
+//     }).Where("age > ?", 18).Find(&users)
+func (db *DB) DryRun() *DB {
+	c := db.WithConnection(dryRunDB)
+	c.db = c.db.Set(dryRunSetting, true)
+	return c
+}
+
+// ToSQL returns the SQL and vars of the first statement fn runs on a dry run clone of the db, see DryRun.
+// When fn only builds a call chain the chain is run as a query
+//     sql, vars, err := db.ToSQL(func(tx jorm.Interface) jorm.Interface {
+//         return tx.Model(&User{}).Where("age > ?", 18).Group("name")
+//     })
+func (db *DB) ToSQL(fn func(tx Interface) Interface) (string, []interface{}, error) {
+	var stmts []*Statement
+	dry := db.DryRun().OnStatement(toSQLHookName, func(stmt *Statement) {
+		switch stmt.Operation {
+		case OperationBegin, OperationCommit, OperationRollback:
+			return
+		}
+		stmts = append(stmts, stmt)
+	})
+
+	result := fn(dry)
+	if result != nil {
+		if err := dryRunError(result.Error()); err != nil {
+			return "", nil, err
+		}
+		if len(stmts) == 0 {
+			rows, err := result.Rows()
+			if err != nil {
+				return "", nil, err
+			}
+			rows.Close()
+		}
+	}
+
+	if len(stmts) == 0 {
+		return "", nil, ErrNoStatement
+	}
+	return stmts[0].SQL, stmts[0].Vars, nil
+}
+
+// dryRunError returns the error of a dry run statement, unless it is because the statement found or changed no records
+func dryRunError(err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows) || errors.Is(err, ErrStaleObject) {
+		return nil
+	}
+	return err
+}
+
+// dryRunConnector opens the connections of dryRunDB
+type dryRunConnector struct{}
+
+// Connect returns a new dry run connection
+func (dryRunConnector) Connect(context.Context) (driver.Conn, error) {
+	return dryRunConn{}, nil
+}
+
+// Driver returns the dry run driver
+func (dryRunConnector) Driver() driver.Driver {
+	return dryRunDriver{}
+}
+
+// dryRunDriver is a driver.Driver whose connections never reach a database
+type dryRunDriver struct{}
+
+// Open returns a new dry run connection whatever the name
+func (dryRunDriver) Open(string) (driver.Conn, error) {
+	return dryRunConn{}, nil
+}
+
+// dryRunConn is a connection whose statements change no rows and find no records
+type dryRunConn struct{}
+
+// Prepare returns a dry run statement
+func (dryRunConn) Prepare(string) (driver.Stmt, error) {
+	return dryRunStmt{}, nil
+}
+
+// Close does nothing
+func (dryRunConn) Close() error {
+	return nil
+}
+
+// Begin returns a transaction that does nothing
+func (dryRunConn) Begin() (driver.Tx, error) {
+	return dryRunTx{}, nil
+}
+
+// ExecContext changes no rows
+func (dryRunConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return dryRunResult{}, nil
+}
+
+// QueryContext finds no records
+func (dryRunConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return dryRunRows{}, nil
+}
+
+// CheckNamedValue accepts every argument as is, so arguments of any type can be passed to a dry run
+func (dryRunConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+// dryRunStmt is a prepared statement that changes no rows and finds no records
+type dryRunStmt struct{}
+
+// Close does nothing
+func (dryRunStmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1 so the number of arguments is not checked
+func (dryRunStmt) NumInput() int {
+	return -1
+}
+
+// Exec changes no rows
+func (dryRunStmt) Exec([]driver.Value) (driver.Result, error) {
+	return dryRunResult{}, nil
+}
+
+// Query finds no records
+func (dryRunStmt) Query([]driver.Value) (driver.Rows, error) {
+	return dryRunRows{}, nil
+}
+
+// dryRunResult is the result of a dry run statement, which inserted and changed nothing
+type dryRunResult struct{}
+
+// LastInsertId returns 0 as nothing was inserted
+func (dryRunResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+// RowsAffected returns 0 as no rows were changed
+func (dryRunResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
+
+// dryRunTx is a transaction that does nothing
+type dryRunTx struct{}
+
+// Commit does nothing
+func (dryRunTx) Commit() error {
+	return nil
+}
+
+// Rollback does nothing
+func (dryRunTx) Rollback() error {
+	return nil
+}
+
+// dryRunRows are the empty result of a dry run query
+type dryRunRows struct{}
+
+// Columns returns no columns
+func (dryRunRows) Columns() []string {
+	return nil
+}
+
+// Close does nothing
+func (dryRunRows) Close() error {
+	return nil
+}
+
+// Next returns io.EOF as there are no rows
+func (dryRunRows) Next([]driver.Value) error {
+	return io.EOF
+}
+
+// compile time check that a dry run connection implements the optional interfaces gorm's statements go through
+var (
+	_ driver.ExecerContext     = dryRunConn{}
+	_ driver.QueryerContext    = dryRunConn{}
+	_ driver.NamedValueChecker = dryRunConn{}
+	_ gorm.SQLCommon           = dryRunDB
+)
@@ -0,0 +1,100 @@
+package jorm_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jloom6/jorm"
+)
+
+func TestToSQL(t *testing.T) {
+	db := open(t)
+	var count int
+	tests := []struct {
+		fn   func(tx jorm.Interface) jorm.Interface
+		sql  string
+		vars string
+	}{
+		{
+			func(tx jorm.Interface) jorm.Interface {
+				return tx.Model(&user{}).Where("name = ?", "alice").Group("name").Having("count(*) > ?", 1).Order("name")
+			},
+			`SELECT * FROM "users"  WHERE (name = ?) GROUP BY name HAVING (count(*) > ?) ORDER BY "name"`, "[alice 1]",
+		},
+		{
+			func(tx jorm.Interface) jorm.Interface { return tx.Create(&user{Name: "bob"}) },
+			`INSERT INTO "users" ("name") VALUES (?)`, "[bob]",
+		},
+		{
+			// First finds no record in a dry run, which is not an error
+			func(tx jorm.Interface) jorm.Interface { return tx.First(&user{}, 3) },
+			`SELECT * FROM "users"  WHERE ("users"."id" = 3) ORDER BY "users"."id" ASC LIMIT 1`, "[]",
+		},
+		{
+			func(tx jorm.Interface) jorm.Interface {
+				return tx.Model(&user{}).Where("id = ?", 1).Update("name", "carol")
+			},
+			`UPDATE "users" SET "name" = ?  WHERE (id = ?)`, "[carol 1]",
+		},
+		{
+			func(tx jorm.Interface) jorm.Interface { return tx.Where("id > ?", 0).Delete(&user{}) },
+			`DELETE FROM "users"  WHERE (id > ?)`, "[0]",
+		},
+		{
+			func(tx jorm.Interface) jorm.Interface { return tx.Model(&user{}).Count(&count) },
+			`SELECT count(*) FROM "users"  `, "[]",
+		},
+	}
+	for _, test := range tests {
+		sql, vars, err := db.ToSQL(test.fn)
+		if err != nil {
+			t.Errorf("ToSQL(%q) = %v", test.sql, err)
+			continue
+		}
+		if sql != test.sql || fmt.Sprint(vars) != test.vars {
+			t.Errorf("ToSQL = %q %v, want %q %s", sql, vars, test.sql, test.vars)
+		}
+	}
+
+	if _, _, err := db.ToSQL(func(tx jorm.Interface) jorm.Interface { return nil }); err != jorm.ErrNoStatement {
+		t.Errorf("ToSQL without a statement = %v", err)
+	}
+	if names := names(t, db); len(names) != 0 {
+		t.Errorf("ToSQL changed the database: %v", names)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	db := open(t)
+	if err := db.Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	var operations []string
+	dry := db.DryRun().OnStatement("record", func(stmt *jorm.Statement) {
+		operations = append(operations, stmt.Operation)
+	})
+	tx := dry.Begin()
+	if err := tx.Create(&user{Name: "bob"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit().Error(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(operations) != "[begin create commit]" {
+		t.Errorf("operations = %v", operations)
+	}
+
+	// queries find no records, like they would on an empty table
+	var users []user
+	if err := dry.Find(&users).Error(); err != nil || len(users) != 0 {
+		t.Errorf("Find = %v %v", users, err)
+	}
+	if err := dry.First(&user{}).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Errorf("First = %v", err)
+	}
+	if names := names(t, db); fmt.Sprint(names) != "[alice]" {
+		t.Errorf("names = %v", names)
+	}
+}
@@ -0,0 +1,227 @@
+package jorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"regexp"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+)
+
+// Sentinel errors that the errors returned by Error are classified as, use errors.Is to check for them
+var (
+	// ErrNotFound is gorm.ErrRecordNotFound so that comparing the error with either keeps working
+	ErrNotFound = gorm.ErrRecordNotFound
+	// ErrDuplicateKey is a unique or primary key violation
+	ErrDuplicateKey = errors.New("jorm: duplicate key")
+	// ErrForeignKeyViolation is a foreign key constraint violation
+	ErrForeignKeyViolation = errors.New("jorm: foreign key violation")
+	// ErrNotNullViolation is a NULL written to a NOT NULL column
+	ErrNotNullViolation = errors.New("jorm: not null violation")
+	// ErrCheckViolation is a check constraint violation
+	ErrCheckViolation = errors.New("jorm: check violation")
+	// ErrDataTooLong is a value that does not fit its column
+	ErrDataTooLong = errors.New("jorm: data too long")
+	// ErrDeadlock is a deadlock that rolled back the transaction
+	ErrDeadlock = errors.New("jorm: deadlock")
+	// ErrLockTimeout is a statement that timed out waiting for a lock or could not take one with NOWAIT
+	ErrLockTimeout = errors.New("jorm: lock wait timeout")
+	// ErrConnection is a broken or refused connection to the database
+	ErrConnection = errors.New("jorm: connection error")
+)
+
+// Error is an error classified as one of the sentinel errors, it keeps the message of the error it wraps so
+// errors.Is matches both Kind and the original error and errors.As reaches the original error
+//     var jormErr *jorm.Error
+//     if errors.Is(err, jorm.ErrDuplicateKey) && errors.As(err, &jormErr) {
+//         log.Printf("%s is taken", jormErr.Constraint)
+//     }
+type Error struct {
+	// Kind is the sentinel error the error is classified as
+	Kind error
+	// Constraint is the name of the violated constraint, index or column when the database reports it
+	Constraint string
+	// Err is the original error
+	Err error
+}
+
+// Error returns the message of the original error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error the error is classified as
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Classifier classifies an error of a dialect, it returns nil for errors it does not know
+type Classifier func(err error) *Error
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   = map[string]Classifier{}
+)
+
+// RegisterClassifier sets the classifier used for the errors of the dialect with the given name,
+// as returned by Dialect().GetName(), replacing any classifier registered before
+func RegisterClassifier(dialect string, classifier Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+
+	classifiers[dialect] = classifier
+}
+
+// Classify returns err classified by the classifier of the dialect, or err itself if it cannot be classified.
+// Errors that already are an *Error and connection errors are classified for every dialect, and of gorm.Errors
+// the first error that can be classified is returned
+func Classify(dialect string, err error) error {
+	if classified := classify(dialect, err); classified != nil {
+		return classified
+	}
+	return err
+}
+
+// classify returns err classified by the classifier of the dialect, nil if it cannot be classified
+func classify(dialect string, err error) error {
+	if err == nil || err == ErrNotFound {
+		return nil
+	}
+	if errs, ok := err.(gorm.Errors); ok {
+		for _, e := range errs {
+			if classified := classify(dialect, e); classified != nil {
+				return classified
+			}
+		}
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	classifiersMu.RLock()
+	classifier := classifiers[dialect]
+	classifiersMu.RUnlock()
+
+	if classifier != nil {
+		if classified := classifier(err); classified != nil {
+			return classified
+		}
+	}
+	if isConnectionError(err) {
+		return &Error{Kind: ErrConnection, Err: err}
+	}
+	return nil
+}
+
+// classifyAny classifies err with every registered classifier, for errors of an unknown dialect
+func classifyAny(err error) error {
+	classifiersMu.RLock()
+	dialects := make([]string, 0, len(classifiers))
+	for dialect := range classifiers {
+		dialects = append(dialects, dialect)
+	}
+	classifiersMu.RUnlock()
+
+	for _, dialect := range dialects {
+		if classified := classify(dialect, err); classified != nil {
+			return classified
+		}
+	}
+	return err
+}
+
+// isConnectionError reports whether err means the connection to the database is broken or could not be made,
+// context.DeadlineExceeded is a net.Error but means the context of the statement expired
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// MySQL error numbers, see https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	mysqlErrTooManyConnections      = 1040
+	mysqlErrAccessDenied            = 1045
+	mysqlErrBadNull                 = 1048
+	mysqlErrServerShutdown          = 1053
+	mysqlErrDupEntry                = 1062
+	mysqlErrLockWaitTimeout         = 1205
+	mysqlErrDeadlock                = 1213
+	mysqlErrNoReferencedRow         = 1216
+	mysqlErrRowIsReferenced         = 1217
+	mysqlErrDataTooLong             = 1406
+	mysqlErrRowIsReferenced2        = 1451
+	mysqlErrNoReferencedRow2        = 1452
+	mysqlErrDupEntryWithKeyName     = 1586
+	mysqlErrLockNowait              = 3572
+	mysqlErrCheckConstraintViolated = 3819
+)
+
+var (
+	mysqlDupKeyRegexp     = regexp.MustCompile(`for key '(?:[^']*\.)?([^'.]+)'`)
+	mysqlConstraintRegexp = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+	mysqlColumnRegexp     = regexp.MustCompile(`[Cc]olumn '([^']+)'`)
+	mysqlCheckRegexp      = regexp.MustCompile(`[Cc]heck constraint '([^']+)'`)
+)
+
+func init() {
+	RegisterClassifier("mysql", classifyMySQL)
+}
+
+// classifyMySQL classifies the errors of go-sql-driver/mysql
+func classifyMySQL(err error) *Error {
+	if errors.Is(err, mysql.ErrInvalidConn) {
+		return &Error{Kind: ErrConnection, Err: err}
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return nil
+	}
+
+	switch mysqlErr.Number {
+	case mysqlErrDupEntry, mysqlErrDupEntryWithKeyName:
+		return &Error{Kind: ErrDuplicateKey, Constraint: submatch(mysqlDupKeyRegexp, mysqlErr.Message), Err: err}
+	case mysqlErrNoReferencedRow, mysqlErrRowIsReferenced, mysqlErrNoReferencedRow2, mysqlErrRowIsReferenced2:
+		return &Error{Kind: ErrForeignKeyViolation, Constraint: submatch(mysqlConstraintRegexp, mysqlErr.Message), Err: err}
+	case mysqlErrBadNull:
+		return &Error{Kind: ErrNotNullViolation, Constraint: submatch(mysqlColumnRegexp, mysqlErr.Message), Err: err}
+	case mysqlErrCheckConstraintViolated:
+		return &Error{Kind: ErrCheckViolation, Constraint: submatch(mysqlCheckRegexp, mysqlErr.Message), Err: err}
+	case mysqlErrDataTooLong:
+		return &Error{Kind: ErrDataTooLong, Constraint: submatch(mysqlColumnRegexp, mysqlErr.Message), Err: err}
+	case mysqlErrDeadlock:
+		return &Error{Kind: ErrDeadlock, Err: err}
+	case mysqlErrLockWaitTimeout, mysqlErrLockNowait:
+		return &Error{Kind: ErrLockTimeout, Err: err}
+	case mysqlErrTooManyConnections, mysqlErrServerShutdown, mysqlErrAccessDenied:
+		return &Error{Kind: ErrConnection, Err: err}
+	}
+	return nil
+}
+
+// submatch returns the first submatch of the regular expression in s, or "" if it does not match
+func submatch(re *regexp.Regexp, s string) string {
+	if match := re.FindStringSubmatch(s); match != nil {
+		return match[1]
+	}
+	return ""
+}
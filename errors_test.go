@@ -0,0 +1,118 @@
+package jorm_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+)
+
+func TestClassifyMySQL(t *testing.T) {
+	tests := []struct {
+		err        *mysql.MySQLError
+		kind       error
+		constraint string
+	}{
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.idx_email'"}, jorm.ErrDuplicateKey, "idx_email"},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"}, jorm.ErrDuplicateKey, "PRIMARY"},
+		{&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"}, jorm.ErrForeignKeyViolation, "fk_orders_user"},
+		{&mysql.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"}, jorm.ErrNotNullViolation, "name"},
+		{&mysql.MySQLError{Number: 3819, Message: "Check constraint 'age_positive' is violated."}, jorm.ErrCheckViolation, "age_positive"},
+		{&mysql.MySQLError{Number: 1406, Message: "Data too long for column 'name' at row 1"}, jorm.ErrDataTooLong, "name"},
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}, jorm.ErrDeadlock, ""},
+		{&mysql.MySQLError{Number: 3572, Message: "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set."}, jorm.ErrLockTimeout, ""},
+		{&mysql.MySQLError{Number: 1040, Message: "Too many connections"}, jorm.ErrConnection, ""},
+	}
+	for _, test := range tests {
+		err := jorm.Classify("mysql", fmt.Errorf("wrapped: %w", test.err))
+
+		var classified *jorm.Error
+		if !errors.Is(err, test.kind) || !errors.As(err, &classified) || classified.Constraint != test.constraint {
+			t.Errorf("Classify(%d) = %#v, want %v on %q", test.err.Number, err, test.kind, test.constraint)
+			continue
+		}
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr != test.err {
+			t.Errorf("Classify(%d) does not wrap the *mysql.MySQLError", test.err.Number)
+		}
+	}
+}
+
+func TestClassifyUnclassified(t *testing.T) {
+	unknown := &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}
+	if err := jorm.Classify("mysql", unknown); err != unknown {
+		t.Errorf("Classify of an unknown error = %#v", err)
+	}
+	if err := jorm.Classify("mysql", gorm.ErrRecordNotFound); err != jorm.ErrNotFound {
+		t.Errorf("Classify(ErrRecordNotFound) = %v", err)
+	}
+	if err := jorm.Classify("mysql", nil); err != nil {
+		t.Errorf("Classify(nil) = %v", err)
+	}
+	if err := jorm.Classify("sqlite3", context.DeadlineExceeded); err != context.DeadlineExceeded {
+		t.Errorf("Classify(DeadlineExceeded) = %#v", err)
+	}
+}
+
+func TestClassifyGormErrors(t *testing.T) {
+	fk := &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails (CONSTRAINT `fk_orders_user`)"}
+	err := jorm.Classify("mysql", gorm.Errors{errors.New("first"), fk})
+	if !errors.Is(err, jorm.ErrForeignKeyViolation) {
+		t.Errorf("Classify(gorm.Errors) = %v", err)
+	}
+}
+
+func TestClassifyConnectionErrors(t *testing.T) {
+	tests := []struct {
+		dialect string
+		err     error
+	}{
+		{"mysql", mysql.ErrInvalidConn},
+		{"mysql", driver.ErrBadConn},
+		// connection errors of database/sql are classified for every dialect
+		{"sqlite3", driver.ErrBadConn},
+	}
+	for _, test := range tests {
+		err := jorm.Classify(test.dialect, test.err)
+		if !errors.Is(err, jorm.ErrConnection) || !errors.Is(err, test.err) {
+			t.Errorf("Classify(%q, %v) = %#v", test.dialect, test.err, err)
+		}
+	}
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	errCustom := errors.New("custom duplicate")
+	jorm.RegisterClassifier("custom", func(err error) *jorm.Error {
+		if err == errCustom {
+			return &jorm.Error{Kind: jorm.ErrDuplicateKey, Constraint: "idx", Err: err}
+		}
+		return nil
+	})
+
+	if err := jorm.Classify("custom", errCustom); !errors.Is(err, jorm.ErrDuplicateKey) || !errors.Is(err, errCustom) {
+		t.Errorf("Classify with a registered classifier = %v", err)
+	}
+	if err := jorm.Classify("mysql", errCustom); err != errCustom {
+		t.Errorf("Classify for another dialect = %v", err)
+	}
+}
+
+func TestErrorOfStatement(t *testing.T) {
+	db := open(t)
+	if err := db.Create(&user{ID: 1, Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	// SQLite errors are not classified but still returned
+	if err := db.Create(&user{ID: 1, Name: "bob"}).Error(); err == nil {
+		t.Error("Create of a duplicate primary key has no error")
+	}
+	var u user
+	if err := db.First(&u, 2).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Errorf("First of a missing record = %v", err)
+	}
+}
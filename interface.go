@@ -1,6 +1,14 @@
 package jorm
 
-//go:generate retool do mockgen -destination=mocks/jorm.go -package=mocks github.com/jloom6/jorm Interface,Row,Rows
+//go:generate retool do mockgen -destination=mocks/jorm.go -package=mocks github.com/jloom6/jorm Interface,Row,Rows,SQLCommon,Association,Callback,CallbackProcessor,Dialect
+//go:generate retool do mockgen -destination=mocks/configurer.go -package=mocks github.com/jloom6/jorm Configurer
+//go:generate retool do mockgen -destination=mocks/result.go -package=mocks github.com/jloom6/jorm Result
+//go:generate retool do mockgen -destination=mocks/query_builder.go -package=mocks github.com/jloom6/jorm QueryBuilder
+//go:generate retool do mockgen -destination=mocks/querier.go -package=mocks github.com/jloom6/jorm Querier
+//go:generate retool do mockgen -destination=mocks/writer.go -package=mocks github.com/jloom6/jorm Writer
+//go:generate retool do mockgen -destination=mocks/migrator.go -package=mocks github.com/jloom6/jorm Migrator
+//go:generate retool do mockgen -destination=mocks/tx_manager.go -package=mocks github.com/jloom6/jorm TxManager
+// mocks/chain.go is maintained by hand on top of mocks/jorm.go, update it when builder funcs are added
 
 import (
 	"context"
@@ -9,28 +17,56 @@ import (
 	"github.com/jinzhu/gorm"
 )
 
-// Interface contains all of the funcs a *gorm.DB has
+// Interface contains all of the funcs a *gorm.DB has, it is made up of smaller interfaces so that
+// code can depend on only the funcs it needs
 type Interface interface {
+	Configurer
+	Result
+	QueryBuilder
+	Querier
+	Writer
+	Migrator
+	TxManager
+}
+
+// Configurer contains the funcs that configure a db and give access to what it wraps
+type Configurer interface {
 	// Adds context propagation
 	WithContext(ctx context.Context) Interface
-	// Allow field values to be accessed via function calls
-	Value() interface{}
-	Error() error
-	RowsAffected() int64
 	// Allow users access to the underlying gorm DB
 	GetGormDB() *gorm.DB
-	// Wrap the functions
 	New() Interface
 	Close() error
 	DB() *sql.DB
-	CommonDB() gorm.SQLCommon
-	Dialect() gorm.Dialect
-	Callback() *gorm.Callback
+	CommonDB() SQLCommon
+	Dialect() Dialect
+	Callback() Callback
 	SetLogger(log mysql.Logger)
+	WithLogger(logger Logger) Interface
 	LogMode(enable bool) Interface
 	BlockGlobalUpdate(enable bool) Interface
 	HasBlockGlobalUpdate() bool
 	SingularTable(enable bool)
+	Debug() Interface
+	UsePrimary() Interface
+	WithoutTenantScope() Interface
+	Set(name string, value interface{}) Interface
+	InstantSet(name string, value interface{}) Interface
+	Get(name string) (value interface{}, ok bool)
+}
+
+// Result contains the funcs that report the outcome of the last operation
+type Result interface {
+	// Allow field values to be accessed via function calls
+	Value() interface{}
+	Error() error
+	RowsAffected() int64
+	AddError(err error) error
+	GetErrors() []error
+}
+
+// QueryBuilder contains the funcs that add conditions to a query without running it
+type QueryBuilder interface {
 	Where(query interface{}, args ...interface{}) Interface
 	Or(query interface{}, args ...interface{}) Interface
 	Not(query interface{}, args ...interface{}) Interface
@@ -46,6 +82,18 @@ type Interface interface {
 	Unscoped() Interface
 	Assign(attrs ...interface{}) Interface
 	Attrs(attrs ...interface{}) Interface
+	Raw(sql string, values ...interface{}) Interface
+	Model(value interface{}) Interface
+	Table(name string) Interface
+	Preload(column string, conditions ...interface{}) Interface
+	ForUpdate() Interface
+	ForShare() Interface
+	SkipLocked() Interface
+	NoWait() Interface
+}
+
+// Querier contains the funcs that read records
+type Querier interface {
 	First(out interface{}, where ...interface{}) Interface
 	Take(out interface{}, where ...interface{}) Interface
 	Last(out interface{}, where ...interface{}) Interface
@@ -53,11 +101,18 @@ type Interface interface {
 	Scan(dest interface{}) Interface
 	Row() Row
 	Rows() (Rows, error)
-	ScanRows(rows *sql.Rows, result interface{}) error
+	ScanRows(rows Rows, result interface{}) error
 	Pluck(column string, value interface{}) Interface
 	Count(value interface{}) Interface
 	Related(value interface{}, foreignKeys ...string) Interface
 	FirstOrInit(out interface{}, where ...interface{}) Interface
+	Association(column string) Association
+	RecordNotFound() bool
+	ToSQL(fn func(tx Interface) Interface) (string, []interface{}, error)
+}
+
+// Writer contains the funcs that create, change or delete records
+type Writer interface {
 	FirstOrCreate(out interface{}, where ...interface{}) Interface
 	Update(attrs ...interface{}) Interface
 	Updates(values interface{}, ignoreProtectedAttrs ...bool) Interface
@@ -66,16 +121,12 @@ type Interface interface {
 	Save(value interface{}) Interface
 	Create(value interface{}) Interface
 	Delete(value interface{}, where ...interface{}) Interface
-	Raw(sql string, values ...interface{}) Interface
 	Exec(sql string, values ...interface{}) Interface
-	Model(value interface{}) Interface
-	Table(name string) Interface
-	Debug() Interface
-	Begin() Interface
-	Commit() Interface
-	Rollback() Interface
 	NewRecord(value interface{}) bool
-	RecordNotFound() bool
+}
+
+// Migrator contains the funcs that change the schema
+type Migrator interface {
 	CreateTable(models ...interface{}) Interface
 	DropTable(values ...interface{}) Interface
 	DropTableIfExists(values ...interface{}) Interface
@@ -88,14 +139,17 @@ type Interface interface {
 	RemoveIndex(indexName string) Interface
 	AddForeignKey(field string, dest string, onDelete string, onUpdate string) Interface
 	RemoveForeignKey(field string, dest string) Interface
-	Association(column string) *gorm.Association
-	Preload(column string, conditions ...interface{}) Interface
-	Set(name string, value interface{}) Interface
-	InstantSet(name string, value interface{}) Interface
-	Get(name string) (value interface{}, ok bool)
 	SetJoinTableHandler(source interface{}, column string, handler gorm.JoinTableHandlerInterface)
-	AddError(err error) error
-	GetErrors() []error
+}
+
+// TxManager contains the funcs that manage transactions
+type TxManager interface {
+	Begin() Interface
+	Commit() Interface
+	Rollback() Interface
+	Transaction(ctx context.Context, fn func(tx Interface) error) error
+	TxDepth() int
+	SavepointName() string
 }
 
 // Row is an interface wrapper for sql.Row
@@ -113,3 +167,59 @@ type Rows interface {
 	Scan(dest ...interface{}) error
 	Close() error
 }
+
+// SQLCommon is an interface wrapper for gorm.SQLCommon, the `*sql.DB` or `*sql.Tx` a db runs its statements on
+type SQLCommon interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Prepare(query string) (*sql.Stmt, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Association is an interface wrapper for gorm.Association
+type Association interface {
+	Find(value interface{}) Association
+	Append(values ...interface{}) Association
+	Replace(values ...interface{}) Association
+	Delete(values ...interface{}) Association
+	Clear() Association
+	Count() int
+	Error() error
+}
+
+// Callback is an interface wrapper for gorm.Callback
+type Callback interface {
+	Create() CallbackProcessor
+	Update() CallbackProcessor
+	Delete() CallbackProcessor
+	Query() CallbackProcessor
+	RowQuery() CallbackProcessor
+}
+
+// CallbackProcessor is an interface wrapper for gorm.CallbackProcessor
+type CallbackProcessor interface {
+	After(callbackName string) CallbackProcessor
+	Before(callbackName string) CallbackProcessor
+	Register(callbackName string, callback func(scope *gorm.Scope))
+	Remove(callbackName string)
+	Replace(callbackName string, callback func(scope *gorm.Scope))
+	Get(callbackName string) func(scope *gorm.Scope)
+}
+
+// Dialect is an interface wrapper for the funcs of gorm.Dialect that are safe to call on a shared db
+type Dialect interface {
+	GetName() string
+	BindVar(i int) string
+	Quote(key string) string
+	DataTypeOf(field *gorm.StructField) string
+	HasIndex(tableName string, indexName string) bool
+	HasForeignKey(tableName string, foreignKeyName string) bool
+	RemoveIndex(tableName string, indexName string) error
+	HasTable(tableName string) bool
+	HasColumn(tableName string, columnName string) bool
+	ModifyColumn(tableName string, columnName string, typ string) error
+	SelectFromDummyTable() string
+	LastInsertIDReturningSuffix(tableName, columnName string) string
+	BuildKeyName(kind, tableName string, fields ...string) string
+	CurrentDatabase() string
+}
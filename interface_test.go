@@ -0,0 +1,71 @@
+package jorm_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/mocks"
+)
+
+// userStore depends on only the role interfaces it uses
+type userStore struct {
+	reads  jorm.Querier
+	writes jorm.Writer
+}
+
+func (s userStore) rename(u *user, name string) error {
+	u.Name = name
+	return s.writes.Save(u).Error()
+}
+
+func (s userStore) count() (int, error) {
+	var n int
+	err := s.reads.Count(&n).Error()
+	return n, err
+}
+
+func TestRoleInterfacesWithDB(t *testing.T) {
+	db := open(t)
+	store := userStore{reads: db.Model(&user{}), writes: db}
+
+	u := user{Name: "alice"}
+	if err := db.Create(&u).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.rename(&u, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.count(); err != nil || n != 1 {
+		t.Errorf("count = %d %v", n, err)
+	}
+	if got := names(t, db); len(got) != 1 || got[0] != "bob" {
+		t.Errorf("users = %v", got)
+	}
+}
+
+func TestRoleInterfacesWithMocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reads := mocks.NewMockQuerier(ctrl)
+	writes := mocks.NewMockWriter(ctrl)
+	result := mocks.NewMockInterface(ctrl)
+	store := userStore{reads: reads, writes: writes}
+
+	u := &user{ID: 1, Name: "alice"}
+	writes.EXPECT().Save(u).Return(result)
+	result.EXPECT().Error().Return(nil)
+	if err := store.rename(u, "bob"); err != nil || u.Name != "bob" {
+		t.Errorf("rename = %+v %v", u, err)
+	}
+
+	reads.EXPECT().Count(gomock.Any()).DoAndReturn(func(value interface{}) jorm.Interface {
+		*value.(*int) = 3
+		return result
+	})
+	result.EXPECT().Error().Return(nil)
+	if n, err := store.count(); err != nil || n != 3 {
+		t.Errorf("count = %d %v", n, err)
+	}
+}
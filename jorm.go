@@ -3,25 +3,64 @@ package jorm
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
-	"github.com/smacker/opentracing-gorm"
 )
 
 // DB is a wrapper struct around a *gorm.DB
 type DB struct {
-	db *gorm.DB
+	db         *gorm.DB
+	ctx        context.Context
+	txDepth    int
+	txOpen     *int32
+	cluster    *cluster
+	usePrimary bool
+}
+
+// sqlTx is implemented by the connection of a db that is in a transaction
+type sqlTx interface {
+	Commit() error
+	Rollback() error
 }
 
 // NewDB returns a new interface wrapper around the given *gorm.DB
 func NewDB(db *gorm.DB) *DB {
-	return &DB{db: db}
+	registerTenantCallbacks(db)
+	registerVersionCallbacks(db)
+	registerLockCallbacks(db)
+
+	d := &DB{db: db}
+	if _, ok := db.CommonDB().(sqlTx); ok {
+		d.txDepth = 1
+	}
+	return d
+}
+
+// clone returns a copy of the current db wrapping the given *gorm.DB
+func (db *DB) clone(gormDB *gorm.DB) *DB {
+	c := *db
+	c.db = gormDB
+	return &c
 }
 
-// WithContext returns a clone of the current db with parent Span ID set to that of the context
+// WithContext returns a clone of the current db whose statements are traced as children of the span of the context,
+// every statement run by the clone uses the context so cancelling it or reaching its deadline stops the statement
 func (db *DB) WithContext(ctx context.Context) Interface {
-	return &DB{db: otgorm.SetSpanToGorm(ctx, db.db)}
+	c := db.clone(tracerOf(db.db).WithContext(ctx, db.db).Set(contextSetting, ctx))
+	c.ctx = ctx
+	return c.withContextConn()
+}
+
+// withContextConn makes the connection of the db use the db's context
+func (db *DB) withContextConn() *DB {
+	if db.ctx != nil {
+		setCommonDB(db.db, withContextConn(db.ctx, db.db.CommonDB()))
+	}
+	return db
 }
 
 // Value is a wrapper function for the Value field
@@ -31,7 +70,7 @@ func (db *DB) Value() interface{} {
 
 // Error is a wrapper function for the Error field
 func (db *DB) Error() error {
-	return db.db.Error
+	return Classify(db.db.Dialect().GetName(), db.db.Error)
 }
 
 // RowsAffected is a wrapper function for the RowsAffected field
@@ -41,51 +80,74 @@ func (db *DB) RowsAffected() int64 {
 
 // New clone a new db connection without search conditions
 func (db *DB) New() Interface {
-	return &DB{db: db.db.New()}
+	return db.clone(db.db.New())
 }
 
-// Close close current db connection.  If database connection is not an io.Closer, returns an error.
+// Close close current db connection and those of its replicas.  If database connection is not an io.Closer, returns an error.
 func (db *DB) Close() error {
-	return db.db.Close()
+	err := db.db.Close()
+	if db.cluster != nil {
+		if db.cluster.monitor != nil {
+			db.cluster.monitor.stop()
+		}
+		for _, replica := range db.cluster.replicas {
+			if replicaErr := replica.Close(); err == nil {
+				err = replicaErr
+			}
+		}
+	}
+	return err
 }
 
 // DB get `*sql.DB` from current connection
 // If the underlying database connection is not a *sql.DB, returns nil
 func (db *DB) DB() *sql.DB {
-	return db.db.DB()
+	conn, _ := db.CommonDB().(*sql.DB)
+	return conn
 }
 
 // CommonDB return the underlying `*sql.DB` or `*sql.Tx` instance, mainly intended to allow coexistence with legacy non-GORM code.
-func (db *DB) CommonDB() gorm.SQLCommon {
-	return db.db.CommonDB()
+func (db *DB) CommonDB() SQLCommon {
+	return unwrapConn(db.db.CommonDB())
+}
+
+// WithConnection returns a clone of the db outside of any transaction that runs its statements on the given
+// connection pool, the dialect keeps the connection of the db for migrations and its own queries
+func (db *DB) WithConnection(conn *sql.DB) *DB {
+	c := db.clone(db.db.Set(connectionSetting, conn))
+	c.txDepth = 0
+	c.txOpen = nil
+	setCommonDB(c.db, conn)
+	return c.withContextConn()
 }
 
 // Dialect get dialect
-func (db *DB) Dialect() gorm.Dialect {
+func (db *DB) Dialect() Dialect {
 	return db.db.Dialect()
 }
 
 // Callback return `Callbacks` container, you could add/change/delete callbacks with it
 //     db.Callback().Create().Register("update_created_at", updateCreated)
 // Refer https://jinzhu.github.io/gorm/development.html#callbacks
-func (db *DB) Callback() *gorm.Callback {
-	return db.db.Callback()
+func (db *DB) Callback() Callback {
+	return &callback{c: db.db.Callback()}
 }
 
 // SetLogger replace default logger
+// Deprecated: use WithLogger, which logs structured entries with levels
 func (db *DB) SetLogger(log mysql.Logger) {
 	db.db.SetLogger(log)
 }
 
 // LogMode set log mode, `true` for detailed logs, `false` for no log, default, will only print error logs
 func (db *DB) LogMode(enable bool) Interface {
-	return &DB{db: db.db.LogMode(enable)}
+	return db.clone(db.db.LogMode(enable).InstantSet(logModeSetting, enable))
 }
 
 // BlockGlobalUpdate if true, generates an error on update/delete without where clause.
 // This is to prevent eventual error with empty objects updates/deletions
 func (db *DB) BlockGlobalUpdate(enable bool) Interface {
-	return &DB{db: db.db.BlockGlobalUpdate(enable)}
+	return db.clone(db.db.BlockGlobalUpdate(enable))
 }
 
 // HasBlockGlobalUpdate return state of block
@@ -100,27 +162,27 @@ func (db *DB) SingularTable(enable bool) {
 
 // Where return a new relation, filter records with given conditions, accepts `map`, `struct` or `string` as conditions, refer http://jinzhu.github.io/gorm/crud.html#query
 func (db *DB) Where(query interface{}, args ...interface{}) Interface {
-	return &DB{db: db.db.Where(query, args...)}
+	return db.clone(db.db.Where(query, args...))
 }
 
 // Or filter records that match before conditions or this one, similar to `Where`
 func (db *DB) Or(query interface{}, args ...interface{}) Interface {
-	return &DB{db: db.db.Or(query, args...)}
+	return db.clone(db.db.Or(query, args...))
 }
 
 // Not filter records that don't match current conditions, similar to `Where`
 func (db *DB) Not(query interface{}, args ...interface{}) Interface {
-	return &DB{db: db.db.Not(query, args...)}
+	return db.clone(db.db.Not(query, args...))
 }
 
 // Limit specify the number of records to be retrieved
 func (db *DB) Limit(limit interface{}) Interface {
-	return &DB{db: db.db.Limit(limit)}
+	return db.clone(db.db.Limit(limit))
 }
 
 // Offset specify the number of records to skip before starting to return the records
 func (db *DB) Offset(offset interface{}) Interface {
-	return &DB{db: db.db.Offset(offset)}
+	return db.clone(db.db.Offset(offset))
 }
 
 // Order specify order when retrieve records from database, set reorder to `true` to overwrite defined conditions
@@ -128,34 +190,34 @@ func (db *DB) Offset(offset interface{}) Interface {
 //     db.Order("name DESC", true) // reorder
 //     db.Order(gorm.Expr("name = ? DESC", "first")) // sql expression
 func (db *DB) Order(value interface{}, reorder ...bool) Interface {
-	return &DB{db: db.db.Order(value, reorder...)}
+	return db.clone(db.db.Order(value, reorder...))
 }
 
 // Select specify fields that you want to retrieve from database when querying, by default, will select all fields;
 // When creating/updating, specify fields that you want to save to database
 func (db *DB) Select(query interface{}, args ...interface{}) Interface {
-	return &DB{db: db.db.Select(query, args...)}
+	return db.clone(db.db.Select(query, args...))
 }
 
 // Omit specify fields that you want to ignore when saving to database for creating, updating
 func (db *DB) Omit(columns ...string) Interface {
-	return &DB{db: db.db.Omit(columns...)}
+	return db.clone(db.db.Omit(columns...))
 }
 
 // Group specify the group method on the find
 func (db *DB) Group(query string) Interface {
-	return &DB{db: db.db.Group(query)}
+	return db.clone(db.db.Group(query))
 }
 
 // Having specify HAVING conditions for GROUP BY
 func (db *DB) Having(query interface{}, values ...interface{}) Interface {
-	return &DB{db: db.db.Having(query, values...)}
+	return db.clone(db.db.Having(query, values...))
 }
 
 // Joins specify Joins conditions
 //     db.Joins("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org").Find(&user)
 func (db *DB) Joins(query string, args ...interface{}) Interface {
-	return &DB{db: db.db.Joins(	query, args...)}
+	return db.clone(db.db.Joins(	query, args...))
 }
 
 // Scopes pass current database connection to arguments `func(*DB) *DB`, which could be used to add conditions dynamically
@@ -172,137 +234,169 @@ func (db *DB) Joins(query string, args ...interface{}) Interface {
 //     db.Scopes(AmountGreaterThan1000, OrderStatus([]string{"paid", "shipped"})).Find(&orders)
 // Refer https://jinzhu.github.io/gorm/crud.html#scopes
 func (db *DB) Scopes(funcs ...func(*gorm.DB) *gorm.DB) Interface {
-	return &DB{db: db.db.Scopes(funcs...)}
+	return db.clone(db.db.Scopes(funcs...))
 }
 
 // Unscoped return all record including deleted record, refer Soft Delete https://jinzhu.github.io/gorm/crud.html#soft-delete
+// Models with a tenant column also require WithoutTenantScope
 func (db *DB) Unscoped() Interface {
-	return &DB{db: db.db.Unscoped()}
+	return db.clone(db.db.Unscoped().InstantSet(unscopedSetting, true))
 }
 
 // Attrs initialize struct with argument if record not found with `FirstOrInit` https://jinzhu.github.io/gorm/crud.html#firstorinit or `FirstOrCreate` https://jinzhu.github.io/gorm/crud.html#firstorcreate
 func (db *DB) Attrs(attrs ...interface{}) Interface {
-	return &DB{db: db.db.Attrs(attrs...)}
+	return db.clone(db.db.Attrs(attrs...))
 }
 
 // Assign assign result with argument regardless it is found or not with `FirstOrInit` https://jinzhu.github.io/gorm/crud.html#firstorinit or `FirstOrCreate` https://jinzhu.github.io/gorm/crud.html#firstorcreate
 func (db *DB) Assign(attrs ...interface{}) Interface {
-	return &DB{db: db.db.Assign(attrs...)}
+	return db.clone(db.db.Assign(attrs...))
 }
 
 // First find first record that match given conditions, order by primary key
 func (db *DB) First(out interface{}, where ...interface{}) Interface {
-	return &DB{db: db.db.First(out, where...)}
+	return db.read(func(gormDB *gorm.DB) *gorm.DB {
+		return gormDB.First(out, where...)
+	})
 }
 
 // Take return a record that match given conditions, the order will depend on the database implementation
 func (db *DB) Take(out interface{}, where ...interface{}) Interface {
-	return &DB{db: db.db.Take(out, where...)}
+	return db.read(func(gormDB *gorm.DB) *gorm.DB {
+		return gormDB.Take(out, where...)
+	})
 }
 
 // Last find last record that match given conditions, order by primary key
 func (db *DB) Last(out interface{}, where ...interface{}) Interface {
-	return &DB{db: db.db.Last(out, where...)}
+	return db.read(func(gormDB *gorm.DB) *gorm.DB {
+		return gormDB.Last(out, where...)
+	})
 }
 
 // Find find records that match given conditions
 func (db *DB) Find(out interface{}, where ...interface{}) Interface {
-	return &DB{db: db.db.Find(out, where...)}
+	return db.read(func(gormDB *gorm.DB) *gorm.DB {
+		return gormDB.Find(out, where...)
+	})
 }
 
 // Scan scan value to a struct
 func (db *DB) Scan(dest interface{}) Interface {
-	return &DB{db: db.db.Scan(dest)}
+	return db.read(func(gormDB *gorm.DB) *gorm.DB {
+		return gormDB.Scan(dest)
+	})
 }
 
 // Row return `*sql.Row` with given conditions
 func (db *DB) Row() Row {
-	return db.db.Row()
+	return db.replicaDB().Row()
 }
 
 // Rows return `*sql.Rows` with given conditions
 func (db *DB) Rows() (Rows, error) {
-	return db.db.Rows()
+	return db.replicaDB().Rows()
 }
 
-// ScanRows scan `*sql.Rows` to give struct
-func (db *DB) ScanRows(rows *sql.Rows, result interface{}) error {
-	return db.db.ScanRows(rows, result)
+// ScanRows scan the current row of rows to give struct, rows can be a `*sql.Rows` or any other Rows such as a mock
+func (db *DB) ScanRows(rows Rows, result interface{}) error {
+	if sqlRows, ok := rows.(*sql.Rows); ok {
+		return db.db.ScanRows(sqlRows, result)
+	}
+	return scanRows(db.db.NewScope(result), rows)
 }
 
 // Pluck used to query single column from a model as a map
 //     var ages []int64
 //     db.Find(&users).Pluck("age", &ages)
 func (db *DB) Pluck(column string, value interface{}) Interface {
-	return &DB{db: db.db.Pluck(column, value)}
+	return db.read(func(gormDB *gorm.DB) *gorm.DB {
+		return gormDB.Pluck(column, value)
+	})
 }
 
 // Count get how many records for a model
 func (db *DB) Count(value interface{}) Interface {
-	return &DB{db: db.db.Count(value)}
+	return db.read(func(gormDB *gorm.DB) *gorm.DB {
+		return gormDB.Count(value)
+	})
 }
 
 // Related get related associations
 func (db *DB) Related(value interface{}, foreignKeys ...string) Interface {
-	return &DB{db: db.db.Related(value, foreignKeys...)}
+	return db.clone(db.db.Related(value, foreignKeys...))
 }
 
 // FirstOrInit find first matched record or initialize a new one with given conditions (only works with struct, map conditions)
 // https://jinzhu.github.io/gorm/crud.html#firstorinit
 func (db *DB) FirstOrInit(out interface{}, where ...interface{}) Interface {
-	return &DB{db: db.db.FirstOrInit(out, where...)}
+	return db.clone(db.db.FirstOrInit(out, where...))
 }
 
 // FirstOrCreate find first matched record or create a new one with given conditions (only works with struct, map conditions)
 // https://jinzhu.github.io/gorm/crud.html#firstorcreate
 func (db *DB) FirstOrCreate(out interface{}, where ...interface{}) Interface {
-	return &DB{db: db.db.FirstOrCreate(out, where...)}
+	return db.clone(db.db.FirstOrCreate(out, where...))
 }
 
 // Update update attributes with callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (db *DB) Update(attrs ...interface{}) Interface {
-	return &DB{db: db.db.Update(attrs...)}
+	return db.clone(db.db.Update(attrs...))
 }
 
 // Updates update attributes with callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (db *DB) Updates(values interface{}, ignoreProtectedAttrs ...bool) Interface {
-	return &DB{db: db.db.Updates(values, ignoreProtectedAttrs...)}
+	return db.clone(db.db.Updates(values, ignoreProtectedAttrs...))
 }
 
 // UpdateColumn update attributes without callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (db *DB) UpdateColumn(attrs ...interface{}) Interface {
-	return &DB{db: db.db.UpdateColumn(attrs...)}
+	return db.clone(db.db.UpdateColumn(attrs...))
 }
 
 // UpdateColumns update attributes without callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (db *DB) UpdateColumns(values interface{}) Interface {
-	return &DB{db: db.db.UpdateColumns(values)}
+	return db.clone(db.db.UpdateColumns(values))
 }
 
 // Save update value in database, if the value doesn't have primary key, will insert it
 func (db *DB) Save(value interface{}) Interface {
-	return &DB{db: db.db.Save(value)}
+	return db.clone(db.db.Save(value))
 }
 
 // Create insert the value into database
 func (db *DB) Create(value interface{}) Interface {
-	return &DB{db: db.db.Create(value)}
+	return db.clone(db.db.Create(value))
 }
 
 // Delete delete value match given conditions, if the value has primary key, then will including the primary key as condition
 func (db *DB) Delete(value interface{}, where ...interface{}) Interface {
-	return &DB{db: db.db.Delete(value, where...)}
+	return db.clone(db.db.Delete(value, where...))
 }
 
-// Raw use raw sql as conditions, won't run it unless invoked by other methods
+// Raw use raw sql as conditions, won't run it unless invoked by other methods, with a context that has a tenant it
+// also requires WithoutTenantScope
 //    db.Raw("SELECT name, age FROM users WHERE name = ?", 3).Scan(&result)
 func (db *DB) Raw(sql string, values ...interface{}) Interface {
-	return &DB{db: db.db.Raw(sql, values...)}
+	c := db.clone(db.db.Raw(sql, values...).Set(rawSetting, true))
+	if !selectRegexp.MatchString(sql) {
+		c.usePrimary = true
+	}
+	return c
 }
 
-// Exec execute raw sql
+// Exec execute raw sql, with a context that has a tenant it also requires WithoutTenantScope
 func (db *DB) Exec(sql string, values ...interface{}) Interface {
-	return &DB{db: db.db.Exec(sql, values...)}
+	if err := tenantExecError(db.db); err != nil {
+		refused := db.db.NewScope(nil).DB()
+		refused.AddError(err)
+		return db.clone(refused)
+	}
+
+	start := time.Now()
+	c := db.clone(db.db.Exec(sql, values...))
+	c.statementDone(OperationExec, sql, values, start, c.db)
+	return c
 }
 
 // Model specify the model you would like to run db operations
@@ -311,32 +405,136 @@ func (db *DB) Exec(sql string, values ...interface{}) Interface {
 //    // if user's primary key is non-blank, will use it as condition, then will only update the user's name to `hello`
 //    db.Model(&user).Update("name", "hello")
 func (db *DB) Model(value interface{}) Interface {
-	return &DB{db: db.db.Model(value)}
+	return db.clone(db.db.Model(value))
 }
 
 // Table specify the table you would like to run db operations
 func (db *DB) Table(name string) Interface {
-	return &DB{db: db.db.Table(name)}
+	return db.clone(db.db.Table(name))
 }
 
 // Debug start debug mode
 func (db *DB) Debug() Interface {
-	return &DB{db: db.db.Debug()}
+	return db.clone(db.db.Debug().InstantSet(logModeSetting, true))
 }
 
-// Begin begin a transaction
+// Begin begin a transaction, if the db is already in a transaction a savepoint is created instead so that the
+// nested transaction can be committed or rolled back on its own
 func (db *DB) Begin() Interface {
-	return &DB{db: db.db.Begin()}
-}
-
-// Commit commit a transaction
+	if db.txDepth == 0 {
+		start := time.Now()
+		gormDB := db.db
+		if pool, ok := unwrapConn(gormDB.CommonDB()).(*sql.DB); ok {
+			// the locking reads of the transaction look up the server version once per pool
+			gormDB = gormDB.Set(poolSetting, pool)
+		}
+		tx := db.clone(gormDB.Begin())
+		tx.txDepth = 1
+		tx.txOpen = nil
+		if tx.db.Error == nil {
+			tx.txOpen = new(int32)
+			*tx.txOpen = 1
+		}
+		tx.statementDone(OperationBegin, "BEGIN", nil, start, tx.db)
+		return tx.withContextConn()
+	}
+
+	tx := db.clone(db.db)
+	tx.txDepth = db.txDepth + 1
+	tx.db = tx.exec("SAVEPOINT " + tx.SavepointName())
+	return tx
+}
+
+// Commit commit a transaction, in a nested transaction the savepoint is released instead
 func (db *DB) Commit() Interface {
-	return &DB{db: db.db.Commit()}
+	if db.txDepth > 1 {
+		return db.endSavepoint(db.exec("RELEASE SAVEPOINT " + db.SavepointName()))
+	}
+
+	start := time.Now()
+	ended := db.endTx()
+	tx := db.clone(db.db.Commit())
+	tx.txDepth = 0
+	tx.txOpen = nil
+	tx.txStatementDone(OperationCommit, "COMMIT", start, ended)
+	return tx
 }
 
-// Rollback rollback a transaction
+// Rollback rollback a transaction, in a nested transaction only the work done since its savepoint is rolled back
 func (db *DB) Rollback() Interface {
-	return &DB{db: db.db.Rollback()}
+	if db.txDepth > 1 {
+		return db.endSavepoint(db.exec("ROLLBACK TO SAVEPOINT " + db.SavepointName()))
+	}
+
+	start := time.Now()
+	ended := db.endTx()
+	tx := db.clone(db.db.Rollback())
+	tx.txDepth = 0
+	tx.txOpen = nil
+	tx.txStatementDone(OperationRollback, "ROLLBACK", start, ended)
+	return tx
+}
+
+// endTx marks the transaction begun by the db as ended and reports whether it was open, a transaction ends with its
+// first commit or rollback even if it fails
+func (db *DB) endTx() bool {
+	return db.txOpen != nil && atomic.CompareAndSwapInt32(db.txOpen, 1, 0)
+}
+
+// exec runs a statement of jorm's own, such as a savepoint, calling the statement hooks of the db
+func (db *DB) exec(sql string) *gorm.DB {
+	start := time.Now()
+	result := db.db.Exec(sql)
+	db.statementDone(OperationExec, sql, nil, start, result)
+	return result
+}
+
+// endSavepoint returns the enclosing transaction of a nested transaction
+func (db *DB) endSavepoint(gormDB *gorm.DB) *DB {
+	tx := db.clone(gormDB)
+	tx.txDepth = db.txDepth - 1
+	return tx
+}
+
+// TxDepth returns how many transactions deep the db is, 0 outside of a transaction and 1 inside a transaction
+// with one more for each nested transaction
+func (db *DB) TxDepth() int {
+	return db.txDepth
+}
+
+// SavepointName returns the name of the savepoint backing the current nested transaction,
+// or an empty string if the db is not in a nested transaction
+func (db *DB) SavepointName() string {
+	if db.txDepth < 2 {
+		return ""
+	}
+	return fmt.Sprintf("jorm_savepoint_%d", db.txDepth-1)
+}
+
+// Transaction start a transaction with the given context and run fn inside it, the transaction is committed if fn
+// returns nil and rolled back if fn returns an error or panics, a panic is re-raised after the rollback
+//     err := db.Transaction(ctx, func(tx jorm.Interface) error {
+//         return tx.Create(&user).Error()
+//     })
+func (db *DB) Transaction(ctx context.Context, fn func(tx Interface) error) error {
+	tx := db.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error()
 }
 
 // NewRecord check if value's primary key is blank
@@ -351,17 +549,17 @@ func (db *DB) RecordNotFound() bool {
 
 // CreateTable create table for models
 func (db *DB) CreateTable(models ...interface{}) Interface {
-	return &DB{db: db.db.CreateTable(models...)}
+	return db.clone(db.db.CreateTable(models...))
 }
 
 // DropTable drop table for models
 func (db *DB) DropTable(values ...interface{}) Interface {
-	return &DB{db: db.db.DropTable(values...)}
+	return db.clone(db.db.DropTable(values...))
 }
 
 // DropTableIfExists drop table if it is exist
 func (db *DB) DropTableIfExists(values ...interface{}) Interface {
-	return &DB{db: db.db.DropTableIfExists(values...)}
+	return db.clone(db.db.DropTableIfExists(values...))
 }
 
 // HasTable check has table or not
@@ -371,65 +569,65 @@ func (db *DB) HasTable(value interface{}) bool {
 
 // AutoMigrate run auto migration for given models, will only add missing fields, won't delete/change current data
 func (db *DB) AutoMigrate(values ...interface{}) Interface {
-	return &DB{db: db.db.AutoMigrate(values...)}
+	return db.clone(db.db.AutoMigrate(values...))
 }
 
 // ModifyColumn modify column to type
 func (db *DB) ModifyColumn(column string, typ string) Interface {
-	return &DB{db: db.db.ModifyColumn(column, typ)}
+	return db.clone(db.db.ModifyColumn(column, typ))
 }
 
 // DropColumn drop a column
 func (db *DB) DropColumn(column string) Interface {
-	return &DB{db: db.db.DropColumn(column)}
+	return db.clone(db.db.DropColumn(column))
 }
 
 // AddIndex add index for columns with given name
 func (db *DB) AddIndex(indexName string, columns ...string) Interface {
-	return &DB{db: db.db.AddIndex(indexName, columns...)}
+	return db.clone(db.db.AddIndex(indexName, columns...))
 }
 
 // AddUniqueIndex add unique index for columns with given name
 func (db *DB) AddUniqueIndex(indexName string, columns ...string) Interface {
-	return &DB{db: db.db.AddUniqueIndex(indexName, columns...)}
+	return db.clone(db.db.AddUniqueIndex(indexName, columns...))
 }
 
 // RemoveIndex remove index with name
 func (db *DB) RemoveIndex(indexName string) Interface {
-	return &DB{db: db.db.RemoveIndex(indexName)}
+	return db.clone(db.db.RemoveIndex(indexName))
 }
 
 // AddForeignKey Add foreign key to the given scope, e.g:
 //     db.Model(&User{}).AddForeignKey("city_id", "cities(id)", "RESTRICT", "RESTRICT")
 func (db *DB) AddForeignKey(field string, dest string, onDelete string, onUpdate string) Interface {
-	return &DB{db: db.db.AddForeignKey(field, dest, onDelete, onUpdate)}
+	return db.clone(db.db.AddForeignKey(field, dest, onDelete, onUpdate))
 }
 
 // RemoveForeignKey Remove foreign key from the given scope, e.g:
 //     db.Model(&User{}).RemoveForeignKey("city_id", "cities(id)")
 func (db *DB) RemoveForeignKey(field string, dest string) Interface {
-	return &DB{db: db.db.RemoveForeignKey(field, dest)}
+	return db.clone(db.db.RemoveForeignKey(field, dest))
 }
 
 // Association start `Association Mode` to handler relations things easir in that mode, refer: https://jinzhu.github.io/gorm/associations.html#association-mode
-func (db *DB) Association(column string) *gorm.Association {
-	return db.db.Association(column)
+func (db *DB) Association(column string) Association {
+	return &association{a: db.db.Association(column)}
 }
 
 // Preload preload associations with given conditions
 //    db.Preload("Orders", "state NOT IN (?)", "cancelled").Find(&users)
 func (db *DB) Preload(column string, conditions ...interface{}) Interface {
-	return &DB{db: db.db.Preload(column, conditions...)}
+	return db.clone(db.db.Preload(column, conditions...))
 }
 
 // Set set setting by name, which could be used in callbacks, will clone a new db, and update its setting
 func (db *DB) Set(name string, value interface{}) Interface {
-	return &DB{db: db.db.Set(name, value)}
+	return db.clone(db.db.Set(name, value))
 }
 
 // InstantSet instant set setting, will affect current db
 func (db *DB) InstantSet(name string, value interface{}) Interface {
-	return &DB{db: db.db.InstantSet(name, value)}
+	return db.clone(db.db.InstantSet(name, value))
 }
 
 // Get get setting by name
@@ -449,7 +647,11 @@ func (db *DB) AddError(err error) error {
 
 // GetErrors get happened errors from the db
 func (db *DB) GetErrors() []error {
-	return db.db.GetErrors()
+	errs := db.db.GetErrors()
+	for i, err := range errs {
+		errs[i] = Classify(db.db.Dialect().GetName(), err)
+	}
+	return errs
 }
 
 // GetGormDB returns the underlying gorm DB
@@ -0,0 +1,189 @@
+package jorm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/jloom6/jorm"
+)
+
+type user struct {
+	ID   uint
+	Name string
+}
+
+var errBoom = errors.New("boom")
+
+// open returns a db of a SQLite database in a temporary directory with the users table
+func open(t *testing.T) *jorm.DB {
+	t.Helper()
+	g, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "jorm.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Close() })
+
+	db := jorm.NewDB(g)
+	if err := db.AutoMigrate(&user{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// names returns the names of the users, in order of their IDs
+func names(t *testing.T, db jorm.Interface) []string {
+	t.Helper()
+	var users []user
+	if err := db.Order("id").Find(&users).Error(); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	return names
+}
+
+func TestTransactionCommits(t *testing.T) {
+	db := open(t)
+
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		return tx.Create(&user{Name: "alice"}).Error()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(t, db); len(got) != 1 || got[0] != "alice" {
+		t.Errorf("users = %v", got)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	db := open(t)
+
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		if err := tx.Create(&user{Name: "alice"}).Error(); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if err != errBoom {
+		t.Fatalf("Transaction = %v", err)
+	}
+	if got := names(t, db); len(got) != 0 {
+		t.Errorf("users = %v", got)
+	}
+}
+
+func TestTransactionRollsBackOnPanic(t *testing.T) {
+	db := open(t)
+
+	func() {
+		defer func() {
+			if r := recover(); r != errBoom {
+				t.Errorf("recovered %v", r)
+			}
+		}()
+		db.Transaction(context.Background(), func(tx jorm.Interface) error {
+			tx.Create(&user{Name: "alice"})
+			panic(errBoom)
+		})
+	}()
+	if got := names(t, db); len(got) != 0 {
+		t.Errorf("users = %v", got)
+	}
+}
+
+func TestNestedTransactionSavepoints(t *testing.T) {
+	var savepoints []string
+	db := open(t).OnStatement("savepoints", func(stmt *jorm.Statement) {
+		if stmt.Operation == jorm.OperationExec {
+			savepoints = append(savepoints, stmt.SQL)
+		}
+	})
+
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		if tx.TxDepth() != 1 || tx.SavepointName() != "" {
+			t.Errorf("transaction depth %d savepoint %q", tx.TxDepth(), tx.SavepointName())
+		}
+		if err := tx.Create(&user{Name: "alice"}).Error(); err != nil {
+			return err
+		}
+
+		err := tx.Transaction(context.Background(), func(nested jorm.Interface) error {
+			if nested.TxDepth() != 2 || nested.SavepointName() != "jorm_savepoint_1" {
+				t.Errorf("nested depth %d savepoint %q", nested.TxDepth(), nested.SavepointName())
+			}
+			if err := nested.Create(&user{Name: "bob"}).Error(); err != nil {
+				return err
+			}
+			return nested.Transaction(context.Background(), func(inner jorm.Interface) error {
+				if inner.TxDepth() != 3 || inner.SavepointName() != "jorm_savepoint_2" {
+					t.Errorf("inner depth %d savepoint %q", inner.TxDepth(), inner.SavepointName())
+				}
+				return inner.Create(&user{Name: "carol"}).Error()
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		err = tx.Transaction(context.Background(), func(nested jorm.Interface) error {
+			if err := nested.Create(&user{Name: "dave"}).Error(); err != nil {
+				return err
+			}
+			return errBoom
+		})
+		if err != errBoom {
+			t.Errorf("failed nested transaction = %v", err)
+		}
+		return tx.Create(&user{Name: "erin"}).Error()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := fmt.Sprint(names(t, db)), "[alice bob carol erin]"; got != want {
+		t.Errorf("users = %s, want %s", got, want)
+	}
+	want := []string{
+		"SAVEPOINT jorm_savepoint_1",
+		"SAVEPOINT jorm_savepoint_2",
+		"RELEASE SAVEPOINT jorm_savepoint_2",
+		"RELEASE SAVEPOINT jorm_savepoint_1",
+		"SAVEPOINT jorm_savepoint_1",
+		"ROLLBACK TO SAVEPOINT jorm_savepoint_1",
+	}
+	if fmt.Sprint(savepoints) != fmt.Sprint(want) {
+		t.Errorf("savepoint statements = %q, want %q", savepoints, want)
+	}
+}
+
+func TestNestedBeginRollback(t *testing.T) {
+	db := open(t)
+
+	tx := db.Begin()
+	tx.Create(&user{Name: "alice"})
+	nested := tx.Begin()
+	if err := nested.Error(); err != nil || nested.TxDepth() != 2 {
+		t.Fatalf("nested Begin: depth %d, %v", nested.TxDepth(), err)
+	}
+	nested.Create(&user{Name: "bob"})
+	if err := nested.Rollback().Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(names(t, tx)); got != "[alice]" {
+		t.Errorf("users after rolling back to the savepoint = %s", got)
+	}
+	if err := tx.Commit().Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(names(t, db)); got != "[alice]" {
+		t.Errorf("users after commit = %s", got)
+	}
+}
@@ -0,0 +1,49 @@
+// Package jormlogrus adapts a logrus logger to jorm.Logger
+//     db = db.WithLogger(jormlogrus.New(logrus.StandardLogger()))
+package jormlogrus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jloom6/jorm"
+	"github.com/sirupsen/logrus"
+)
+
+// Logger is a jorm.Logger that logs with a logrus.FieldLogger
+type Logger struct {
+	logger logrus.FieldLogger
+}
+
+// New returns a Logger that logs with the given logrus.FieldLogger, such as a *logrus.Logger or *logrus.Entry
+func New(logger logrus.FieldLogger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Log logs the message at the logrus level matching the level with the key/value pairs as fields
+func (l *Logger) Log(ctx context.Context, level jorm.Level, msg string, keyvals ...interface{}) {
+	fields := make(logrus.Fields, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		if i+1 == len(keyvals) {
+			fields["!BADKEY"] = keyvals[i]
+			break
+		}
+		fields[key] = keyvals[i+1]
+	}
+
+	entry := l.logger.WithFields(fields)
+	switch level {
+	case jorm.LevelDebug:
+		entry.Debug(msg)
+	case jorm.LevelWarn:
+		entry.Warn(msg)
+	case jorm.LevelError:
+		entry.Error(msg)
+	default:
+		entry.Info(msg)
+	}
+}
+
+// compile time check that Logger implements the interface
+var _ jorm.Logger = (*Logger)(nil)
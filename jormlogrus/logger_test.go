@@ -0,0 +1,43 @@
+package jormlogrus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormlogrus"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLog(t *testing.T) {
+	base, hook := test.NewNullLogger()
+	base.SetLevel(logrus.DebugLevel)
+	logger := jormlogrus.New(base)
+
+	levels := map[jorm.Level]logrus.Level{
+		jorm.LevelDebug: logrus.DebugLevel,
+		jorm.LevelInfo:  logrus.InfoLevel,
+		jorm.LevelWarn:  logrus.WarnLevel,
+		jorm.LevelError: logrus.ErrorLevel,
+	}
+	for level, want := range levels {
+		hook.Reset()
+		logger.Log(context.Background(), level, "statement", "rows", 3)
+
+		entry := hook.LastEntry()
+		if entry == nil || entry.Level != want || entry.Message != "statement" || entry.Data["rows"] != 3 {
+			t.Errorf("Log at %v = %+v", level, entry)
+		}
+	}
+}
+
+func TestLogOddKeyvals(t *testing.T) {
+	base, hook := test.NewNullLogger()
+	jormlogrus.New(base.WithField("app", "jorm")).Log(context.Background(), jorm.LevelInfo, "odd", "rows", 3, "dangling")
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Data["app"] != "jorm" || entry.Data["rows"] != 3 || entry.Data["!BADKEY"] != "dangling" {
+		t.Errorf("entry = %+v", entry)
+	}
+}
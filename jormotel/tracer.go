@@ -0,0 +1,141 @@
+// Package jormotel traces the statements of a jorm.DB with OpenTelemetry
+//     db = db.WithTracer(jormotel.New())
+//     db.WithContext(ctx).Find(&users)
+package jormotel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the OpenTelemetry tracer
+const instrumentationName = "github.com/jloom6/jorm/jormotel"
+
+// Attribute keys of the OpenTelemetry database semantic conventions
+const (
+	dbSystemKey       = attribute.Key("db.system")
+	dbNameKey         = attribute.Key("db.name")
+	dbStatementKey    = attribute.Key("db.statement")
+	dbOperationKey    = attribute.Key("db.operation")
+	dbSQLTableKey     = attribute.Key("db.sql.table")
+	dbRowsAffectedKey = attribute.Key("db.rows_affected")
+)
+
+// dbSystems maps gorm dialect names to the db.system values of the semantic conventions
+var dbSystems = map[string]string{
+	"mysql":    "mysql",
+	"postgres": "postgresql",
+	"sqlite3":  "sqlite",
+	"mssql":    "mssql",
+}
+
+// Option configures a Tracer
+type Option func(t *Tracer)
+
+// WithTracerProvider sets the provider the tracer is created with, the global provider is used by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		t.tracer = provider.Tracer(instrumentationName)
+	}
+}
+
+// WithDBName sets the db.name attribute of every span
+func WithDBName(name string) Option {
+	return func(t *Tracer) {
+		t.attrs = append(t.attrs, dbNameKey.String(name))
+	}
+}
+
+// WithAttributes adds the given attributes to every span
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(t *Tracer) {
+		t.attrs = append(t.attrs, attrs...)
+	}
+}
+
+// Tracer is a jorm.Tracer that creates a client span per statement, a child of the span of the context given to
+// WithContext, with the attributes of the OpenTelemetry database semantic conventions
+type Tracer struct {
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+// New returns a Tracer configured by the given options
+func New(opts ...Option) *Tracer {
+	t := &Tracer{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.tracer == nil {
+		t.tracer = otel.GetTracerProvider().Tracer(instrumentationName)
+	}
+	return t
+}
+
+// WithContext returns the db as it is, jorm keeps the context the span of a statement is started from
+func (t *Tracer) WithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return db
+}
+
+// TraceStatement records a span for the statement, from its start time to its end
+func (t *Tracer) TraceStatement(stmt *jorm.Statement) {
+	attrs := append([]attribute.KeyValue{
+		dbSystemKey.String(dbSystem(stmt.Dialect)),
+		dbOperationKey.String(stmt.Operation),
+		dbRowsAffectedKey.Int64(stmt.RowsAffected),
+	}, t.attrs...)
+	if stmt.SQL != "" {
+		attrs = append(attrs, dbStatementKey.String(strings.TrimSpace(stmt.SQL)))
+	}
+	if stmt.Table != "" {
+		attrs = append(attrs, dbSQLTableKey.String(stmt.Table))
+	}
+
+	_, span := t.tracer.Start(stmt.Context, spanName(stmt),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(stmt.StartTime),
+		trace.WithAttributes(attrs...),
+	)
+
+	// not finding a record is an expected outcome rather than a failure of the statement
+	if stmt.Error != nil && stmt.Error != jorm.ErrNotFound {
+		span.RecordError(stmt.Error)
+		span.SetStatus(codes.Error, stmt.Error.Error())
+	}
+	span.End(trace.WithTimestamp(stmt.StartTime.Add(stmt.Duration)))
+}
+
+// TraceID returns the trace ID of the span of the context
+func (t *Tracer) TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// spanName returns the name of the span of a statement, its operation and table
+func spanName(stmt *jorm.Statement) string {
+	if stmt.Table == "" {
+		return stmt.Operation
+	}
+	return stmt.Operation + " " + stmt.Table
+}
+
+// dbSystem returns the db.system value for a gorm dialect name
+func dbSystem(dialect string) string {
+	if system, ok := dbSystems[dialect]; ok {
+		return system
+	}
+	return dialect
+}
+
+// compile time check that Tracer implements the interface
+var _ jorm.Tracer = (*Tracer)(nil)
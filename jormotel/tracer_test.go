@@ -0,0 +1,131 @@
+package jormotel_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormotel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type user struct {
+	ID   uint
+	Name string
+}
+
+// traced returns a db of a SQLite database with the users table traced to an in-memory exporter
+func traced(t *testing.T, opts ...jormotel.Option) (*jorm.DB, *tracetest.InMemoryExporter, trace.TracerProvider) {
+	t.Helper()
+	g, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "tracer.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Close() })
+	if err := g.AutoMigrate(&user{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	opts = append([]jormotel.Option{jormotel.WithTracerProvider(provider)}, opts...)
+	return jorm.NewDB(g).WithTracer(jormotel.New(opts...)), exporter, provider
+}
+
+// attributes returns the attributes of a span by key
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestStatementSpans(t *testing.T) {
+	db, exporter, provider := traced(t, jormotel.WithDBName("app"))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	if err := db.WithContext(ctx).Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "create users" || span.SpanKind != trace.SpanKindClient {
+		t.Errorf("span %q of kind %v", span.Name, span.SpanKind)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("the span is not a child of the span of the context")
+	}
+	if span.Status.Code != codes.Unset {
+		t.Errorf("status = %v", span.Status)
+	}
+
+	attrs := attributes(span)
+	want := map[attribute.Key]string{
+		"db.system":    "sqlite",
+		"db.name":      "app",
+		"db.operation": "create",
+		"db.sql.table": "users",
+	}
+	for key, value := range want {
+		if got := attrs[key].AsString(); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if attrs["db.rows_affected"].AsInt64() != 1 {
+		t.Errorf("db.rows_affected = %v", attrs["db.rows_affected"].Emit())
+	}
+	if attrs["db.statement"].AsString() == "" {
+		t.Error("db.statement is empty")
+	}
+}
+
+func TestErrorSpans(t *testing.T) {
+	db, exporter, _ := traced(t)
+
+	if err := db.First(&user{}, 99).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Fatal(err)
+	}
+	if err := db.Exec("INSERT INTO missing VALUES (1)").Error(); err == nil {
+		t.Fatal("Exec into a missing table has no error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans", len(spans))
+	}
+	// not finding a record is not a failure
+	if spans[0].Name != "find users" || spans[0].Status.Code != codes.Unset {
+		t.Errorf("span %q status %v", spans[0].Name, spans[0].Status)
+	}
+	if spans[1].Name != "exec" || spans[1].Status.Code != codes.Error || len(spans[1].Events) != 1 {
+		t.Errorf("span %q status %v with %d events", spans[1].Name, spans[1].Status, len(spans[1].Events))
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	tracer := jormotel.New()
+	if id := tracer.TraceID(context.Background()); id != "" {
+		t.Errorf("TraceID without a span = %q", id)
+	}
+
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+	if id := tracer.TraceID(ctx); id != span.SpanContext().TraceID().String() {
+		t.Errorf("TraceID = %q", id)
+	}
+}
@@ -0,0 +1,233 @@
+// Package jormprom records Prometheus metrics for the statements run by a jorm.DB
+//     metrics := jormprom.New(jormprom.Options{})
+//     prometheus.MustRegister(metrics)
+//     db := metrics.Instrument(jorm.NewDB(gormDB))
+package jormprom
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/jloom6/jorm"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// hookName is the name the statement hook of Metrics is added under
+const hookName = "jormprom"
+
+// Options configures the metrics created by New
+type Options struct {
+	// Namespace prefixes the name of every metric, defaults to "jorm"
+	Namespace string
+	// ConstLabels are added to every metric, for example to tell databases apart
+	ConstLabels prometheus.Labels
+	// Buckets are the buckets of the latency histogram in seconds, defaults to prometheus.DefBuckets
+	Buckets []float64
+}
+
+// Metrics is a prometheus.Collector of the statements of the dbs it instruments and of their connection pools.
+// Statements are labelled by operation, one of the jorm.Operation constants, and table
+type Metrics struct {
+	statements   *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	rowsAffected *prometheus.CounterVec
+	errors       *prometheus.CounterVec
+	transactions prometheus.Gauge
+
+	mu    sync.Mutex
+	pools []*sql.DB
+
+	maxOpen           *prometheus.Desc
+	open              *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+// New returns Metrics that still have to be registered with a prometheus.Registerer
+func New(opts Options) *Metrics {
+	if opts.Namespace == "" {
+		opts.Namespace = "jorm"
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = prometheus.DefBuckets
+	}
+	labels := []string{"operation", "table"}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(opts.Namespace, "pool", name), help, nil, opts.ConstLabels)
+	}
+
+	return &Metrics{
+		statements: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "statements_total",
+			Help:        "Number of statements run.",
+			ConstLabels: opts.ConstLabels,
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Name:        "statement_duration_seconds",
+			Help:        "Latency of statements in seconds.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     opts.Buckets,
+		}, labels),
+		rowsAffected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "rows_affected_total",
+			Help:        "Number of rows found by queries or changed by other statements.",
+			ConstLabels: opts.ConstLabels,
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "errors_total",
+			Help:        "Number of statements that failed, by class of error.",
+			ConstLabels: opts.ConstLabels,
+		}, append(labels, "class")),
+		transactions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Name:        "open_transactions",
+			Help:        "Number of transactions that have begun and not yet been committed or rolled back.",
+			ConstLabels: opts.ConstLabels,
+		}),
+
+		maxOpen:           desc("max_open_connections", "Maximum number of open connections to the database."),
+		open:              desc("open_connections", "Number of established connections both in use and idle."),
+		inUse:             desc("in_use_connections", "Number of connections currently in use."),
+		idle:              desc("idle_connections", "Number of idle connections."),
+		waitCount:         desc("wait_count_total", "Total number of connections waited for."),
+		waitDuration:      desc("wait_duration_seconds_total", "Total time blocked waiting for a new connection."),
+		maxIdleClosed:     desc("max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns."),
+		maxLifetimeClosed: desc("max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime."),
+	}
+}
+
+// Instrument returns a clone of the db whose statements are observed, and collects the stats of its connection pool
+func (m *Metrics) Instrument(db *jorm.DB) *jorm.DB {
+	if pool := db.DB(); pool != nil {
+		m.addPool(pool)
+	}
+	return db.OnStatement(hookName, m.Observe)
+}
+
+// addPool adds a connection pool to collect the stats of, unless it already is
+func (m *Metrics) addPool(pool *sql.DB) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.pools {
+		if p == pool {
+			return
+		}
+	}
+	m.pools = append(m.pools, pool)
+}
+
+// Observe records a statement, it is the jorm.StatementHook added by Instrument
+func (m *Metrics) Observe(stmt *jorm.Statement) {
+	m.statements.WithLabelValues(stmt.Operation, stmt.Table).Inc()
+	m.duration.WithLabelValues(stmt.Operation, stmt.Table).Observe(stmt.Duration.Seconds())
+	if stmt.RowsAffected > 0 {
+		m.rowsAffected.WithLabelValues(stmt.Operation, stmt.Table).Add(float64(stmt.RowsAffected))
+	}
+	if stmt.Error != nil {
+		m.errors.WithLabelValues(stmt.Operation, stmt.Table, ErrorClass(stmt.Error)).Inc()
+	}
+
+	switch stmt.Operation {
+	case jorm.OperationBegin:
+		if stmt.Error == nil {
+			m.transactions.Inc()
+		}
+	case jorm.OperationCommit, jorm.OperationRollback:
+		// a transaction that failed to begin was not counted, and one that already ended was already uncounted
+		if stmt.EndedTransaction {
+			m.transactions.Dec()
+		}
+	}
+}
+
+// ErrorClass returns the class label of an error, the snake cased name of the jorm sentinel it is classified as
+// or "other"
+func ErrorClass(err error) string {
+	switch {
+	case errors.Is(err, jorm.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, jorm.ErrDuplicateKey):
+		return "duplicate_key"
+	case errors.Is(err, jorm.ErrForeignKeyViolation):
+		return "foreign_key_violation"
+	case errors.Is(err, jorm.ErrNotNullViolation):
+		return "not_null_violation"
+	case errors.Is(err, jorm.ErrCheckViolation):
+		return "check_violation"
+	case errors.Is(err, jorm.ErrDataTooLong):
+		return "data_too_long"
+	case errors.Is(err, jorm.ErrDeadlock):
+		return "deadlock"
+	case errors.Is(err, jorm.ErrLockTimeout):
+		return "lock_timeout"
+	case errors.Is(err, jorm.ErrConnection):
+		return "connection"
+	}
+	return "other"
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.statements.Describe(ch)
+	m.duration.Describe(ch)
+	m.rowsAffected.Describe(ch)
+	m.errors.Describe(ch)
+	m.transactions.Describe(ch)
+
+	ch <- m.maxOpen
+	ch <- m.open
+	ch <- m.inUse
+	ch <- m.idle
+	ch <- m.waitCount
+	ch <- m.waitDuration
+	ch <- m.maxIdleClosed
+	ch <- m.maxLifetimeClosed
+}
+
+// Collect implements prometheus.Collector, the pool gauges are the sum of the stats of every instrumented pool
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.statements.Collect(ch)
+	m.duration.Collect(ch)
+	m.rowsAffected.Collect(ch)
+	m.errors.Collect(ch)
+	m.transactions.Collect(ch)
+
+	m.mu.Lock()
+	pools := append([]*sql.DB(nil), m.pools...)
+	m.mu.Unlock()
+	if len(pools) == 0 {
+		return
+	}
+
+	var stats sql.DBStats
+	for _, pool := range pools {
+		s := pool.Stats()
+		stats.MaxOpenConnections += s.MaxOpenConnections
+		stats.OpenConnections += s.OpenConnections
+		stats.InUse += s.InUse
+		stats.Idle += s.Idle
+		stats.WaitCount += s.WaitCount
+		stats.WaitDuration += s.WaitDuration
+		stats.MaxIdleClosed += s.MaxIdleClosed
+		stats.MaxLifetimeClosed += s.MaxLifetimeClosed
+	}
+
+	ch <- prometheus.MustNewConstMetric(m.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(m.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(m.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(m.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(m.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(m.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(m.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(m.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
@@ -0,0 +1,152 @@
+package jormprom_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormprom"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type user struct {
+	ID   uint
+	Name string
+}
+
+// instrument returns a db of a SQLite database with the users table instrumented by the metrics of a new registry
+func instrument(t *testing.T) (*jorm.DB, *prometheus.Registry) {
+	t.Helper()
+	g, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "metrics.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Close() })
+	if err := g.AutoMigrate(&user{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := jormprom.New(jormprom.Options{})
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(metrics)
+	return metrics.Instrument(jorm.NewDB(g)), registry
+}
+
+// gauge returns the expected exposition of the open_transactions gauge
+func gauge(value int) string {
+	return fmt.Sprintf(`
+# HELP jorm_open_transactions Number of transactions that have begun and not yet been committed or rolled back.
+# TYPE jorm_open_transactions gauge
+jorm_open_transactions %d
+`, value)
+}
+
+func TestStatements(t *testing.T) {
+	db, registry := instrument(t)
+
+	for _, name := range []string{"alice", "bob"} {
+		if err := db.Create(&user{Name: name}).Error(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var users []user
+	if err := db.Find(&users).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.First(&user{}, 99).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP jorm_errors_total Number of statements that failed, by class of error.
+# TYPE jorm_errors_total counter
+jorm_errors_total{class="not_found",operation="find",table="users"} 1
+# HELP jorm_rows_affected_total Number of rows found by queries or changed by other statements.
+# TYPE jorm_rows_affected_total counter
+jorm_rows_affected_total{operation="create",table="users"} 2
+jorm_rows_affected_total{operation="find",table="users"} 2
+# HELP jorm_statements_total Number of statements run.
+# TYPE jorm_statements_total counter
+jorm_statements_total{operation="create",table="users"} 2
+jorm_statements_total{operation="find",table="users"} 2
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"jorm_errors_total", "jorm_rows_affected_total", "jorm_statements_total")
+	if err != nil {
+		t.Error(err)
+	}
+	if n, err := testutil.GatherAndCount(registry, "jorm_statement_duration_seconds"); err != nil || n != 2 {
+		t.Errorf("duration series = %d %v", n, err)
+	}
+	if n, err := testutil.GatherAndCount(registry, "jorm_pool_open_connections"); err != nil || n != 1 {
+		t.Errorf("pool series = %d %v", n, err)
+	}
+}
+
+func TestOpenTransactions(t *testing.T) {
+	db, registry := instrument(t)
+
+	tx := db.Begin()
+	nested := tx.Begin()
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(gauge(1)), "jorm_open_transactions"); err != nil {
+		t.Errorf("after Begin: %v", err)
+	}
+	nested.Rollback()
+	if err := tx.Commit().Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(gauge(0)), "jorm_open_transactions"); err != nil {
+		t.Errorf("after Commit: %v", err)
+	}
+}
+
+func TestOpenTransactionsDeferredRollback(t *testing.T) {
+	db, registry := instrument(t)
+
+	// the deferred rollback of a committed transaction must not count it again
+	for i := 0; i < 3; i++ {
+		func() {
+			tx := db.Begin()
+			defer tx.Rollback()
+			if err := tx.Create(&user{Name: "alice"}).Error(); err != nil {
+				t.Fatal(err)
+			}
+			if err := tx.Commit().Error(); err != nil {
+				t.Fatal(err)
+			}
+		}()
+	}
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		tx.Rollback()
+		return errors.New("rolled back twice")
+	})
+	if err == nil {
+		t.Fatal("Transaction returned no error")
+	}
+
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(gauge(0)), "jorm_open_transactions"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := map[error]string{
+		jorm.ErrNotFound: "not_found",
+		jorm.Classify("mysql", &mysql.MySQLError{Number: 1062}): "duplicate_key",
+		jorm.Classify("mysql", &mysql.MySQLError{Number: 1213}): "deadlock",
+		errors.New("boom"): "other",
+	}
+	for err, want := range tests {
+		if got := jormprom.ErrorClass(err); got != want {
+			t.Errorf("ErrorClass(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
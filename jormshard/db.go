@@ -0,0 +1,1313 @@
+package jormshard
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+)
+
+// DB is a jorm.Interface that routes every operation to the shards of its shard key. The key is taken from the
+// record of Create, Save, Delete and Model, from `column = ?` and `column IN (?)` conditions, map and struct
+// conditions given to Where or inline, or from ShardKey.
+// Queries without a key run on every shard: Find merges the records of the shards, sorted by Order with Offset and
+// Limit applied to the merged records, First and Last return the first and last record of every shard by Order and
+// primary key, Take the first found in shard order and Count sums the counts. Scan, Pluck and Rows merge the
+// results in shard order and fail with ErrScatterOrder if they are ordered, limited or offset. Records are not merged
+// by text columns, whose collation only the database knows, Find and First ordered by one fail with ErrScatterOrder.
+// Updates and deletes without a key, and migrations, run on every shard, Exec only with AllShards.
+// Create, Save and FirstOrCreate need a key. A transaction runs on the shard of its first operation and its
+// operations that would run on another shard, or on every shard, fail with ErrCrossShard
+type DB struct {
+	shards            []*jorm.DB
+	opts              Options
+	chain             []func(db jorm.Interface) jorm.Interface
+	wheres            []where
+	model             interface{}
+	scatter           bool
+	keys              []interface{}
+	all               bool
+	orders            []interface{}
+	limit             interface{}
+	offset            interface{}
+	blockGlobalUpdate bool
+	settings          map[string]interface{}
+	tx                *shardTx
+	value             interface{}
+	errs              []error
+	rowsAffected      int64
+}
+
+// New returns a DB routing to the given shards, the index of a shard is its position
+func New(opts Options, shards ...*gorm.DB) *DB {
+	if len(shards) == 0 {
+		panic("jormshard: New requires at least one shard")
+	}
+	if opts.Shard == nil {
+		opts.Shard = HashShard
+	}
+
+	s := &DB{opts: opts, settings: map[string]interface{}{}}
+	for _, shard := range shards {
+		s.shards = append(s.shards, jorm.NewDB(shard))
+	}
+	return s
+}
+
+// MapShards returns a clone of the db whose shards are replaced by what fn returns for them, to add statement hooks
+// or instrument every shard
+//     db = db.MapShards(metrics.Instrument)
+func (s *DB) MapShards(fn func(shard *jorm.DB) *jorm.DB) *DB {
+	c := s.clone()
+	c.shards = make([]*jorm.DB, len(s.shards))
+	for i, shard := range s.shards {
+		c.shards[i] = fn(shard)
+	}
+	return c
+}
+
+// Shard returns the shard of a key, to run what the router does not support on it
+func (s *DB) Shard(key interface{}) *jorm.DB {
+	return s.shards[s.shardsOf([]interface{}{key})[0]]
+}
+
+// ShardKey returns a clone of the db whose operations run on the shards of the keys, whatever their records and
+// conditions, for example for Raw and Exec
+//     db.ShardKey(customerID).Exec("UPDATE orders SET status = ? WHERE customer_id = ?", "closed", customerID)
+func (s *DB) ShardKey(keys ...interface{}) *DB {
+	c := s.clone()
+	c.keys = keys
+	return c
+}
+
+// AllShards returns a clone of the db whose Exec runs on every shard when it has no shard key, without it Exec
+// fails with ErrNoShardKey so that a statement such as an INSERT is not repeated on every shard by mistake
+//     db.AllShards().Exec("DELETE FROM sessions WHERE expires_at < ?", time.Now())
+func (s *DB) AllShards() *DB {
+	c := s.clone()
+	c.all = true
+	return c
+}
+
+// clone returns a copy of the db
+func (s *DB) clone() *DB {
+	c := *s
+	c.chain = append([]func(db jorm.Interface) jorm.Interface(nil), s.chain...)
+	c.wheres = append([]where(nil), s.wheres...)
+	c.orders = append([]interface{}(nil), s.orders...)
+	c.rowsAffected = 0
+	return &c
+}
+
+// with returns a clone of the db that calls op on the shards it runs on
+func (s *DB) with(op func(db jorm.Interface) jorm.Interface) *DB {
+	c := s.clone()
+	c.chain = append(c.chain, op)
+	return c
+}
+
+// gormDB returns the gorm db of the first shard, which is used for what is the same on every shard
+func (s *DB) gormDB() *gorm.DB {
+	return s.shards[0].GetGormDB()
+}
+
+// on returns the shard at the index with the calls of the db, its transaction if the db is in one
+func (s *DB) on(shard int) (jorm.Interface, error) {
+	db := jorm.Interface(s.shards[shard])
+	if s.tx != nil {
+		if err := s.tx.pin(s, shard); err != nil {
+			return nil, err
+		}
+		db = s.tx.db
+	}
+	for _, op := range s.chain {
+		db = op(db)
+	}
+	return db, nil
+}
+
+// checkTx returns ErrCrossShard for an operation of a transaction that runs on more than one shard
+func (s *DB) checkTx(targets []int) error {
+	if s.tx != nil && len(targets) > 1 {
+		return ErrCrossShard
+	}
+	return nil
+}
+
+// done returns a clone holding the outcome of an operation
+func (s *DB) done(value interface{}, rowsAffected int64, errs ...error) *DB {
+	c := s.clone()
+	c.value = value
+	c.rowsAffected = rowsAffected
+	for _, err := range errs {
+		if err != nil {
+			c.errs = append(append([]error(nil), c.errs...), err)
+		}
+	}
+	return c
+}
+
+// doneWith returns a clone holding the outcome of an operation on a single shard
+func (s *DB) doneWith(result jorm.Interface) *DB {
+	return s.done(result.Value(), result.RowsAffected(), result.GetErrors()...)
+}
+
+// each runs an operation on every target shard and sums the rows affected
+func (s *DB) each(targets []int, value interface{}, fn func(db jorm.Interface) jorm.Interface) *DB {
+	if err := s.checkTx(targets); err != nil {
+		return s.done(value, 0, err)
+	}
+
+	var (
+		rowsAffected int64
+		errs         []error
+	)
+	for _, shard := range targets {
+		db, err := s.on(shard)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result := fn(db)
+		rowsAffected += result.RowsAffected()
+		errs = append(errs, result.GetErrors()...)
+		if len(targets) == 1 {
+			value = result.Value()
+		}
+	}
+	return s.done(value, rowsAffected, errs...)
+}
+
+// keyed runs an operation that needs a shard key on the shard of the key of the value
+func (s *DB) keyed(value interface{}, fn func(db jorm.Interface) jorm.Interface) *DB {
+	var targets []int
+	if s.keys != nil {
+		targets = s.shardsOf(s.keys)
+	} else if key, ok := s.valueKey(value); ok {
+		targets = s.shardsOf([]interface{}{key})
+	}
+	if len(targets) != 1 {
+		return s.done(value, 0, ErrNoShardKey)
+	}
+	return s.each(targets, value, fn)
+}
+
+// allShards returns the indexes of every shard
+func (s *DB) allShards() []int {
+	all := make([]int, len(s.shards))
+	for i := range all {
+		all[i] = i
+	}
+	return all
+}
+
+// gather runs a query into out on every target shard and merges the records when out is a pointer to a slice, or
+// returns the first record found otherwise. Records of queries on more than one shard are sorted by Order with Offset
+// and Limit applied once merged, queries of other values fail with ErrScatterOrder if they are ordered or paged
+func (s *DB) gather(out interface{}, targets []int, records bool, fn func(db jorm.Interface, out interface{}) jorm.Interface) *DB {
+	if err := s.checkTx(targets); err != nil {
+		return s.done(out, 0, err)
+	}
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return s.first(out, targets, "", fn)
+	}
+
+	scatter := len(targets) > 1 && s.paged()
+	var (
+		keys          []sortKey
+		offset, limit = pageOf(s.offset), pageOf(s.limit)
+	)
+	if scatter {
+		var ok bool
+		if keys, ok = parseOrders(s.orders); !ok || !records || !isRecordType(v.Elem().Type().Elem()) {
+			return s.done(out, 0, ErrScatterOrder)
+		}
+	}
+
+	merged := reflect.MakeSlice(v.Elem().Type(), 0, 0)
+	var errs []error
+	for _, shard := range targets {
+		db, err := s.on(shard)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if scatter {
+			// every shard returns the records up to the end of the page, which is then taken from the merged ones
+			db = db.Offset(-1).Limit(-1)
+			if limit >= 0 && offset > 0 {
+				db = db.Limit(offset + limit)
+			} else if limit >= 0 {
+				db = db.Limit(limit)
+			}
+		}
+		part := reflect.New(v.Elem().Type())
+		result := fn(db, part.Interface())
+		errs = append(errs, result.GetErrors()...)
+		merged = reflect.AppendSlice(merged, part.Elem())
+	}
+
+	if scatter && len(errs) == 0 {
+		if err := s.sortRecords(merged, keys); err != nil {
+			return s.done(out, 0, err)
+		}
+		merged = page(merged, offset, limit)
+	}
+	v.Elem().Set(merged)
+	return s.done(out, int64(merged.Len()), errs...)
+}
+
+// first runs a query into out on the target shards. On more than one shard it returns the first record by Order and
+// then by primary key in the direction of primaryOrder, or the first record found in shard order if it is not
+// ordered at all
+func (s *DB) first(out interface{}, targets []int, primaryOrder string, fn func(db jorm.Interface, out interface{}) jorm.Interface) *DB {
+	if err := s.checkTx(targets); err != nil {
+		return s.done(out, 0, err)
+	}
+
+	keys, ok := parseOrders(s.orders)
+	if len(targets) > 1 && (!ok || pageOf(s.offset) >= 0) {
+		return s.done(out, 0, ErrScatterOrder)
+	}
+	if len(targets) > 1 && primaryOrder != "" {
+		keys = append(keys, sortKey{primary: true, desc: primaryOrder == "DESC"})
+	}
+	if len(targets) == 1 || len(keys) == 0 {
+		for _, shard := range targets {
+			db, err := s.on(shard)
+			if err != nil {
+				return s.done(out, 0, err)
+			}
+			if result := fn(db, out); !result.RecordNotFound() {
+				return s.doneWith(result)
+			}
+		}
+		return s.done(out, 0, jorm.ErrNotFound)
+	}
+
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || !isRecordType(v.Elem().Type()) {
+		return s.done(out, 0, ErrScatterOrder)
+	}
+	candidates := reflect.MakeSlice(reflect.SliceOf(v.Elem().Type()), 0, len(targets))
+	for _, shard := range targets {
+		db, err := s.on(shard)
+		if err != nil {
+			return s.done(out, 0, err)
+		}
+		candidate := reflect.New(v.Elem().Type())
+		candidate.Elem().Set(v.Elem())
+		result := fn(db, candidate.Interface())
+		if result.RecordNotFound() {
+			continue
+		}
+		if err := result.Error(); err != nil {
+			return s.doneWith(result)
+		}
+		candidates = reflect.Append(candidates, candidate.Elem())
+	}
+	if candidates.Len() == 0 {
+		return s.done(out, 0, jorm.ErrNotFound)
+	}
+
+	if err := s.sortRecords(candidates, keys); err != nil {
+		return s.done(out, 0, err)
+	}
+	v.Elem().Set(candidates.Index(0))
+	return s.done(out, 1)
+}
+
+// WithContext returns a clone of the db whose statements use the context
+func (s *DB) WithContext(ctx context.Context) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.WithContext(ctx)
+	})
+}
+
+// GetGormDB returns the gorm db of the first shard, Shard returns the shard of a key
+func (s *DB) GetGormDB() *gorm.DB {
+	return s.gormDB()
+}
+
+// New returns a clone of the db without conditions or shard keys
+func (s *DB) New() jorm.Interface {
+	c := s.clone()
+	c.chain, c.wheres = nil, nil
+	if s.tx != nil {
+		c.chain = s.tx.chain[:len(s.tx.chain):len(s.tx.chain)]
+	}
+	c.model, c.scatter, c.keys, c.all = nil, false, nil, false
+	c.orders, c.limit, c.offset = nil, nil, nil
+	c.value, c.errs = nil, nil
+	return c
+}
+
+// Close closes every shard and returns the first error
+func (s *DB) Close() error {
+	var err error
+	for _, shard := range s.shards {
+		if closeErr := shard.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// DB returns the connection pool of the first shard
+func (s *DB) DB() *sql.DB {
+	return s.shards[0].DB()
+}
+
+// CommonDB returns the connection of the shard the transaction of the db runs on, or of the first shard
+func (s *DB) CommonDB() jorm.SQLCommon {
+	if s.tx != nil && s.tx.db != nil {
+		return s.tx.db.CommonDB()
+	}
+	return s.shards[0].CommonDB()
+}
+
+// Dialect returns the dialect of the first shard
+func (s *DB) Dialect() jorm.Dialect {
+	return s.shards[0].Dialect()
+}
+
+// Callback returns the callbacks of the first shard, gorm shares them with every db opened with the default callbacks
+func (s *DB) Callback() jorm.Callback {
+	return s.shards[0].Callback()
+}
+
+// SetLogger replaces the logger of every shard
+func (s *DB) SetLogger(log mysql.Logger) {
+	for _, shard := range s.shards {
+		shard.SetLogger(log)
+	}
+}
+
+// WithLogger returns a clone of the db that logs with the given logger
+func (s *DB) WithLogger(logger jorm.Logger) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.WithLogger(logger)
+	})
+}
+
+// LogMode returns a clone of the db that logs its statements if enable is true
+func (s *DB) LogMode(enable bool) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.LogMode(enable)
+	})
+}
+
+// BlockGlobalUpdate returns a clone of the db that refuses updates and deletes without conditions if enable is true
+func (s *DB) BlockGlobalUpdate(enable bool) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.BlockGlobalUpdate(enable)
+	})
+	c.blockGlobalUpdate = enable
+	return c
+}
+
+// HasBlockGlobalUpdate return state of block
+func (s *DB) HasBlockGlobalUpdate() bool {
+	return s.blockGlobalUpdate
+}
+
+// SingularTable use singular table by default on every shard
+func (s *DB) SingularTable(enable bool) {
+	for _, shard := range s.shards {
+		shard.SingularTable(enable)
+	}
+}
+
+// Debug returns a clone of the db that logs its statements
+func (s *DB) Debug() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Debug()
+	})
+}
+
+// UsePrimary returns a clone of the db whose reads run on the primary of shards with replicas
+func (s *DB) UsePrimary() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.UsePrimary()
+	})
+}
+
+// WithoutTenantScope returns a clone of the db whose statements are not restricted to a tenant
+func (s *DB) WithoutTenantScope() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.WithoutTenantScope()
+	})
+}
+
+// Set returns a clone of the db with the setting
+func (s *DB) Set(name string, value interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Set(name, value)
+	})
+	c.set(name, value)
+	return c
+}
+
+// InstantSet returns a clone of the db with the setting, gorm applies it to the current statement too
+func (s *DB) InstantSet(name string, value interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.InstantSet(name, value)
+	})
+	c.set(name, value)
+	return c
+}
+
+// set stores a setting for Get
+func (s *DB) set(name string, value interface{}) {
+	settings := make(map[string]interface{}, len(s.settings)+1)
+	for k, v := range s.settings {
+		settings[k] = v
+	}
+	settings[name] = value
+	s.settings = settings
+}
+
+// Get returns a setting of the db, or of the first shard if the db has not set it
+func (s *DB) Get(name string) (value interface{}, ok bool) {
+	if value, ok := s.settings[name]; ok {
+		return value, true
+	}
+	return s.shards[0].Get(name)
+}
+
+// Value returns the value of the last operation, the record or records it ran with
+func (s *DB) Value() interface{} {
+	return s.value
+}
+
+// Error returns the error of the chain, a gorm.Errors when more than one shard failed
+func (s *DB) Error() error {
+	switch len(s.errs) {
+	case 0:
+		return nil
+	case 1:
+		return s.errs[0]
+	}
+	return gorm.Errors(s.errs)
+}
+
+// RowsAffected returns how many records the last operation found or changed on every shard it ran on
+func (s *DB) RowsAffected() int64 {
+	return s.rowsAffected
+}
+
+// AddError adds an error to the chain
+func (s *DB) AddError(err error) error {
+	if err != nil {
+		s.errs = append(s.errs, err)
+	}
+	return err
+}
+
+// GetErrors returns the errors of the chain
+func (s *DB) GetErrors() []error {
+	return s.errs
+}
+
+// Where adds conditions, `column = ?` and `column IN (?)` comparisons, maps and structs with the shard key route
+// the query
+func (s *DB) Where(query interface{}, args ...interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Where(query, args...)
+	})
+	c.wheres = append(c.wheres, where{query: query, args: args})
+	return c
+}
+
+// Or adds conditions, the query then runs on every shard as its shard key conditions no longer restrict it
+func (s *DB) Or(query interface{}, args ...interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Or(query, args...)
+	})
+	c.scatter = true
+	return c
+}
+
+// Not adds NOT conditions
+func (s *DB) Not(query interface{}, args ...interface{}) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Not(query, args...)
+	})
+}
+
+// Limit specify the number of records to be retrieved, from the records of every shard merged without a shard key
+func (s *DB) Limit(limit interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Limit(limit)
+	})
+	c.limit = limit
+	return c
+}
+
+// Offset specify the number of records to skip, of the records of every shard merged without a shard key
+func (s *DB) Offset(offset interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Offset(offset)
+	})
+	c.offset = offset
+	return c
+}
+
+// Order specify order of the records, the records of every shard are merged in that order without a shard key for
+// `column [ASC|DESC]` lists
+func (s *DB) Order(value interface{}, reorder ...bool) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Order(value, reorder...)
+	})
+	if len(reorder) > 0 && reorder[0] {
+		c.orders = nil
+	}
+	if value != nil && value != "" {
+		c.orders = append(c.orders, value)
+	}
+	return c
+}
+
+// Select specify fields that you want to retrieve from database when querying
+func (s *DB) Select(query interface{}, args ...interface{}) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Select(query, args...)
+	})
+}
+
+// Omit specify fields that you want to ignore when saving to database for creating, updating
+func (s *DB) Omit(columns ...string) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Omit(columns...)
+	})
+}
+
+// Group specify the group method on the find of each shard
+func (s *DB) Group(query string) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Group(query)
+	})
+}
+
+// Having specify HAVING conditions for GROUP BY
+func (s *DB) Having(query interface{}, values ...interface{}) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Having(query, values...)
+	})
+}
+
+// Joins specify Joins conditions, the joined tables must be on the same shard
+func (s *DB) Joins(query string, args ...interface{}) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Joins(query, args...)
+	})
+}
+
+// Scopes pass current database connection to arguments `func(*DB) *DB`, which could be used to add conditions dynamically
+func (s *DB) Scopes(funcs ...func(*gorm.DB) *gorm.DB) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Scopes(funcs...)
+	})
+}
+
+// Unscoped return all record including deleted record
+func (s *DB) Unscoped() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Unscoped()
+	})
+}
+
+// Assign assign result with argument regardless it is found or not with FirstOrInit or FirstOrCreate
+func (s *DB) Assign(attrs ...interface{}) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Assign(attrs...)
+	})
+}
+
+// Attrs initialize struct with argument if record not found with FirstOrInit or FirstOrCreate
+func (s *DB) Attrs(attrs ...interface{}) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Attrs(attrs...)
+	})
+}
+
+// Raw use raw sql as conditions, it runs on the shards of ShardKey or on every shard
+func (s *DB) Raw(sql string, values ...interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Raw(sql, values...)
+	})
+	c.scatter = true
+	return c
+}
+
+// Model specify the model you would like to run db operations on, its shard key routes them
+func (s *DB) Model(value interface{}) jorm.Interface {
+	c := s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Model(value)
+	})
+	c.model = value
+	return c
+}
+
+// Table specify the table you would like to run db operations on, the shard key column is the one of Options
+func (s *DB) Table(name string) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Table(name)
+	})
+}
+
+// Preload preload associations with given conditions, the associations must be on the same shard
+func (s *DB) Preload(column string, conditions ...interface{}) jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.Preload(column, conditions...)
+	})
+}
+
+// ForUpdate locks the rows the queries read until the transaction ends
+func (s *DB) ForUpdate() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.ForUpdate()
+	})
+}
+
+// ForShare takes a shared lock on the rows the queries read until the transaction ends
+func (s *DB) ForShare() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.ForShare()
+	})
+}
+
+// SkipLocked skips the rows other transactions have locked
+func (s *DB) SkipLocked() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.SkipLocked()
+	})
+}
+
+// NoWait fails rather than wait for rows other transactions have locked
+func (s *DB) NoWait() jorm.Interface {
+	return s.with(func(db jorm.Interface) jorm.Interface {
+		return db.NoWait()
+	})
+}
+
+// First find first record that match given conditions, order by primary key, across every shard without a shard key
+func (s *DB) First(out interface{}, where ...interface{}) jorm.Interface {
+	return s.first(out, s.targets(out, where), "ASC", func(db jorm.Interface, out interface{}) jorm.Interface {
+		return db.First(out, where...)
+	})
+}
+
+// Take return a record that match given conditions, the first found in shard order without a shard key unless it is
+// ordered
+func (s *DB) Take(out interface{}, where ...interface{}) jorm.Interface {
+	return s.first(out, s.targets(out, where), "", func(db jorm.Interface, out interface{}) jorm.Interface {
+		return db.Take(out, where...)
+	})
+}
+
+// Last find last record that match given conditions, order by primary key, across every shard without a shard key
+func (s *DB) Last(out interface{}, where ...interface{}) jorm.Interface {
+	return s.first(out, s.targets(out, where), "DESC", func(db jorm.Interface, out interface{}) jorm.Interface {
+		return db.Last(out, where...)
+	})
+}
+
+// Find find records that match given conditions, merging the records of every shard in the order of Order without a
+// shard key
+func (s *DB) Find(out interface{}, where ...interface{}) jorm.Interface {
+	return s.gather(out, s.targets(out, where), true, func(db jorm.Interface, out interface{}) jorm.Interface {
+		return db.Find(out, where...)
+	})
+}
+
+// Scan scan value to a struct, merging the records of every shard into a slice without a shard key
+func (s *DB) Scan(dest interface{}) jorm.Interface {
+	return s.gather(dest, s.targets(nil, nil), false, func(db jorm.Interface, dest interface{}) jorm.Interface {
+		return db.Scan(dest)
+	})
+}
+
+// Row return the row of a query on a single shard, its Scan returns ErrNoShardKey if the query has no shard key
+func (s *DB) Row() jorm.Row {
+	targets := s.targets(nil, nil)
+	if len(targets) != 1 {
+		return errRow{err: ErrNoShardKey}
+	}
+	db, err := s.on(targets[0])
+	if err != nil {
+		return errRow{err: err}
+	}
+	return db.Row()
+}
+
+// Rows return the rows of a query, those of every shard one after the other without a shard key, which fails with
+// ErrScatterOrder if the query is ordered or paged
+func (s *DB) Rows() (jorm.Rows, error) {
+	targets := s.targets(nil, nil)
+	if err := s.checkTx(targets); err != nil {
+		return nil, err
+	}
+	if len(targets) > 1 && s.paged() {
+		return nil, ErrScatterOrder
+	}
+
+	rows := &multiRows{}
+	for _, shard := range targets {
+		db, err := s.on(shard)
+		if err == nil {
+			var r jorm.Rows
+			if r, err = db.Rows(); err == nil {
+				rows.rows = append(rows.rows, r)
+				continue
+			}
+		}
+		rows.Close()
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ScanRows scan the current row of rows to give struct
+func (s *DB) ScanRows(rows jorm.Rows, result interface{}) error {
+	return s.shards[0].ScanRows(rows, result)
+}
+
+// Pluck used to query single column from a model as a map, merging the values of every shard without a shard key
+func (s *DB) Pluck(column string, value interface{}) jorm.Interface {
+	return s.gather(value, s.targets(nil, nil), false, func(db jorm.Interface, value interface{}) jorm.Interface {
+		return db.Pluck(column, value)
+	})
+}
+
+// Count get how many records for a model, the sum of the counts of every shard without a shard key
+func (s *DB) Count(value interface{}) jorm.Interface {
+	targets := s.targets(nil, nil)
+	if err := s.checkTx(targets); err != nil {
+		return s.done(value, 0, err)
+	}
+	v := reflect.ValueOf(value)
+	if len(targets) == 1 || v.Kind() != reflect.Ptr {
+		return s.each(targets, value, func(db jorm.Interface) jorm.Interface {
+			return db.Count(value)
+		})
+	}
+
+	total := reflect.New(v.Elem().Type()).Elem()
+	var errs []error
+	for _, shard := range targets {
+		db, err := s.on(shard)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count := reflect.New(v.Elem().Type())
+		errs = append(errs, db.Count(count.Interface()).GetErrors()...)
+		add(total, count.Elem())
+	}
+	v.Elem().Set(total)
+	return s.done(value, 0, errs...)
+}
+
+// add adds the number src to dst
+func add(dst, src reflect.Value) {
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst.SetInt(dst.Int() + src.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		dst.SetUint(dst.Uint() + src.Uint())
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(dst.Float() + src.Float())
+	}
+}
+
+// Related get related associations, from the shard of the model
+func (s *DB) Related(value interface{}, foreignKeys ...string) jorm.Interface {
+	return s.gather(value, s.targets(nil, nil), false, func(db jorm.Interface, value interface{}) jorm.Interface {
+		return db.Related(value, foreignKeys...)
+	})
+}
+
+// FirstOrInit find first matched record or initialize a new one with given conditions (only works with struct, map conditions)
+func (s *DB) FirstOrInit(out interface{}, where ...interface{}) jorm.Interface {
+	targets := s.targets(out, where)
+	if len(targets) == 1 {
+		return s.each(targets, out, func(db jorm.Interface) jorm.Interface {
+			return db.FirstOrInit(out, where...)
+		})
+	}
+
+	found := s.first(out, targets, "ASC", func(db jorm.Interface, out interface{}) jorm.Interface {
+		return db.First(out, where...)
+	})
+	if !found.RecordNotFound() {
+		return found
+	}
+	// initializing the record runs the same query on a shard, which finds nothing either
+	shard := 0
+	if s.tx != nil && s.tx.db != nil {
+		shard = s.tx.shard
+	}
+	return s.each([]int{shard}, out, func(db jorm.Interface) jorm.Interface {
+		return db.FirstOrInit(out, where...)
+	})
+}
+
+// Association returns the association of the model on its shard, its Error is ErrNoShardKey if the model has no
+// shard key
+func (s *DB) Association(column string) jorm.Association {
+	targets := s.targets(nil, nil)
+	if len(targets) != 1 {
+		return errAssociation{err: ErrNoShardKey}
+	}
+	db, err := s.on(targets[0])
+	if err != nil {
+		return errAssociation{err: err}
+	}
+	return db.Association(column)
+}
+
+// RecordNotFound check if returning ErrRecordNotFound error
+func (s *DB) RecordNotFound() bool {
+	for _, err := range s.errs {
+		if err == gorm.ErrRecordNotFound {
+			return true
+		}
+	}
+	return false
+}
+
+// ToSQL returns the SQL and vars of the first statement fn runs on a dry run of the first shard
+func (s *DB) ToSQL(fn func(tx jorm.Interface) jorm.Interface) (string, []interface{}, error) {
+	db := jorm.Interface(s.shards[0])
+	for _, op := range s.chain {
+		db = op(db)
+	}
+	return db.ToSQL(fn)
+}
+
+// FirstOrCreate find first matched record or create a new one with given conditions, on the shard of the key
+// of the record or conditions
+func (s *DB) FirstOrCreate(out interface{}, where ...interface{}) jorm.Interface {
+	targets := s.targets(out, where)
+	if len(targets) != 1 {
+		return s.done(out, 0, ErrNoShardKey)
+	}
+	return s.each(targets, out, func(db jorm.Interface) jorm.Interface {
+		return db.FirstOrCreate(out, where...)
+	})
+}
+
+// Update update attributes with callbacks, on every shard without a shard key
+func (s *DB) Update(attrs ...interface{}) jorm.Interface {
+	return s.each(s.targets(nil, nil), s.model, func(db jorm.Interface) jorm.Interface {
+		return db.Update(attrs...)
+	})
+}
+
+// Updates update attributes with callbacks, on every shard without a shard key
+func (s *DB) Updates(values interface{}, ignoreProtectedAttrs ...bool) jorm.Interface {
+	return s.each(s.targets(nil, nil), s.model, func(db jorm.Interface) jorm.Interface {
+		return db.Updates(values, ignoreProtectedAttrs...)
+	})
+}
+
+// UpdateColumn update attributes without callbacks, on every shard without a shard key
+func (s *DB) UpdateColumn(attrs ...interface{}) jorm.Interface {
+	return s.each(s.targets(nil, nil), s.model, func(db jorm.Interface) jorm.Interface {
+		return db.UpdateColumn(attrs...)
+	})
+}
+
+// UpdateColumns update attributes without callbacks, on every shard without a shard key
+func (s *DB) UpdateColumns(values interface{}) jorm.Interface {
+	return s.each(s.targets(nil, nil), s.model, func(db jorm.Interface) jorm.Interface {
+		return db.UpdateColumns(values)
+	})
+}
+
+// Save update value in database, if the value doesn't have primary key, will insert it, on the shard of its key
+func (s *DB) Save(value interface{}) jorm.Interface {
+	return s.keyed(value, func(db jorm.Interface) jorm.Interface {
+		return db.Save(value)
+	})
+}
+
+// Create insert the value into database, on the shard of its key
+func (s *DB) Create(value interface{}) jorm.Interface {
+	return s.keyed(value, func(db jorm.Interface) jorm.Interface {
+		return db.Create(value)
+	})
+}
+
+// Delete delete value match given conditions, on every shard without a shard key
+func (s *DB) Delete(value interface{}, where ...interface{}) jorm.Interface {
+	return s.each(s.targets(value, where), value, func(db jorm.Interface) jorm.Interface {
+		return db.Delete(value, where...)
+	})
+}
+
+// Exec execute raw sql on the shards of ShardKey, or on every shard with AllShards, it fails with ErrNoShardKey
+// otherwise
+func (s *DB) Exec(sql string, values ...interface{}) jorm.Interface {
+	var targets []int
+	switch {
+	case s.keys != nil:
+		targets = s.shardsOf(s.keys)
+	case s.all:
+		targets = s.allShards()
+	default:
+		return s.done(nil, 0, ErrNoShardKey)
+	}
+	return s.each(targets, nil, func(db jorm.Interface) jorm.Interface {
+		return db.Exec(sql, values...)
+	})
+}
+
+// NewRecord check if value's primary key is blank
+func (s *DB) NewRecord(value interface{}) bool {
+	return s.shards[0].NewRecord(value)
+}
+
+// migrate runs a migration on every shard
+func (s *DB) migrate(fn func(db jorm.Interface) jorm.Interface) jorm.Interface {
+	return s.each(s.allShards(), nil, fn)
+}
+
+// CreateTable create table for models on every shard
+func (s *DB) CreateTable(models ...interface{}) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.CreateTable(models...)
+	})
+}
+
+// DropTable drop table for models on every shard
+func (s *DB) DropTable(values ...interface{}) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.DropTable(values...)
+	})
+}
+
+// DropTableIfExists drop table if it is exist on every shard
+func (s *DB) DropTableIfExists(values ...interface{}) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.DropTableIfExists(values...)
+	})
+}
+
+// HasTable check if every shard has the table
+func (s *DB) HasTable(value interface{}) bool {
+	for _, shard := range s.shards {
+		if !shard.HasTable(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// AutoMigrate run auto migration for given models on every shard
+func (s *DB) AutoMigrate(values ...interface{}) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.AutoMigrate(values...)
+	})
+}
+
+// ModifyColumn modify column to type on every shard
+func (s *DB) ModifyColumn(column string, typ string) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.ModifyColumn(column, typ)
+	})
+}
+
+// DropColumn drop a column on every shard
+func (s *DB) DropColumn(column string) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.DropColumn(column)
+	})
+}
+
+// AddIndex add index for columns with given name on every shard
+func (s *DB) AddIndex(indexName string, columns ...string) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.AddIndex(indexName, columns...)
+	})
+}
+
+// AddUniqueIndex add unique index for columns with given name on every shard, unique within each shard
+func (s *DB) AddUniqueIndex(indexName string, columns ...string) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.AddUniqueIndex(indexName, columns...)
+	})
+}
+
+// RemoveIndex remove index with name on every shard
+func (s *DB) RemoveIndex(indexName string) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.RemoveIndex(indexName)
+	})
+}
+
+// AddForeignKey Add foreign key to the given scope on every shard
+func (s *DB) AddForeignKey(field string, dest string, onDelete string, onUpdate string) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.AddForeignKey(field, dest, onDelete, onUpdate)
+	})
+}
+
+// RemoveForeignKey Remove foreign key from the given scope on every shard
+func (s *DB) RemoveForeignKey(field string, dest string) jorm.Interface {
+	return s.migrate(func(db jorm.Interface) jorm.Interface {
+		return db.RemoveForeignKey(field, dest)
+	})
+}
+
+// SetJoinTableHandler set a model's join table handler for a relation on every shard
+func (s *DB) SetJoinTableHandler(source interface{}, column string, handler gorm.JoinTableHandlerInterface) {
+	for _, shard := range s.shards {
+		shard.SetJoinTableHandler(source, column, handler)
+	}
+}
+
+// Begin begin a transaction, it begins on the shard of its first operation
+func (s *DB) Begin() jorm.Interface {
+	c := s.clone()
+	c.tx = &shardTx{parent: s.tx, chain: c.chain}
+	c.chain = nil
+	return c
+}
+
+// Commit commit the transaction on its shard
+func (s *DB) Commit() jorm.Interface {
+	return s.endTx(func(db jorm.Interface) jorm.Interface {
+		return db.Commit()
+	})
+}
+
+// Rollback rollback the transaction on its shard
+func (s *DB) Rollback() jorm.Interface {
+	return s.endTx(func(db jorm.Interface) jorm.Interface {
+		return db.Rollback()
+	})
+}
+
+// endTx commits or rolls back the transaction, which did nothing if it never ran an operation
+func (s *DB) endTx(fn func(db jorm.Interface) jorm.Interface) jorm.Interface {
+	if s.tx == nil {
+		return s.done(nil, 0, gorm.ErrInvalidTransaction)
+	}
+
+	c := s.clone()
+	c.tx = s.tx.parent
+	c.chain = s.tx.chain
+	if s.tx.db == nil {
+		return c
+	}
+	return c.done(nil, 0, fn(s.tx.db).GetErrors()...)
+}
+
+// Transaction start a transaction with the given context and run fn inside it, the transaction is committed if fn
+// returns nil and rolled back if fn returns an error or panics, a panic is re-raised after the rollback
+func (s *DB) Transaction(ctx context.Context, fn func(tx jorm.Interface) error) error {
+	tx := s.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error()
+}
+
+// TxDepth returns how many transactions deep the db is
+func (s *DB) TxDepth() int {
+	depth := 0
+	for tx := s.tx; tx != nil; tx = tx.parent {
+		depth++
+	}
+	return depth
+}
+
+// SavepointName returns the name of the savepoint of a nested transaction that has begun on its shard
+func (s *DB) SavepointName() string {
+	if s.tx == nil || s.tx.db == nil {
+		return ""
+	}
+	return s.tx.db.SavepointName()
+}
+
+// shardTx is a transaction that begins on the shard of its first operation
+type shardTx struct {
+	parent *shardTx
+	// chain are the calls of the db when the transaction began
+	chain []func(db jorm.Interface) jorm.Interface
+	shard int
+	db    jorm.Interface
+}
+
+// pin begins the transaction on the shard, it fails with ErrCrossShard if it began on another
+func (tx *shardTx) pin(s *DB, shard int) error {
+	if tx.db != nil {
+		if tx.shard != shard {
+			return ErrCrossShard
+		}
+		return nil
+	}
+
+	db := jorm.Interface(s.shards[shard])
+	if tx.parent != nil {
+		if err := tx.parent.pin(s, shard); err != nil {
+			return err
+		}
+		db = tx.parent.db
+	}
+	for _, op := range tx.chain {
+		db = op(db)
+	}
+	db = db.Begin()
+	if err := db.Error(); err != nil {
+		return err
+	}
+	tx.shard, tx.db = shard, db
+	return nil
+}
+
+// errRow is the row of a query that could not run
+type errRow struct {
+	err error
+}
+
+// Scan returns the error of the query
+func (r errRow) Scan(dest ...interface{}) error {
+	return r.err
+}
+
+// errAssociation is the association of a model without a shard
+type errAssociation struct {
+	err error
+}
+
+// Find does nothing
+func (a errAssociation) Find(value interface{}) jorm.Association {
+	return a
+}
+
+// Append does nothing
+func (a errAssociation) Append(values ...interface{}) jorm.Association {
+	return a
+}
+
+// Replace does nothing
+func (a errAssociation) Replace(values ...interface{}) jorm.Association {
+	return a
+}
+
+// Delete does nothing
+func (a errAssociation) Delete(values ...interface{}) jorm.Association {
+	return a
+}
+
+// Clear does nothing
+func (a errAssociation) Clear() jorm.Association {
+	return a
+}
+
+// Count returns 0
+func (a errAssociation) Count() int {
+	return 0
+}
+
+// Error returns why the association has no shard
+func (a errAssociation) Error() error {
+	return a.err
+}
+
+// multiRows are the rows of the shards of a query one after the other
+type multiRows struct {
+	rows []jorm.Rows
+	pos  int
+	err  error
+}
+
+// Next moves to the next row, of the next shard once the rows of a shard are done
+func (r *multiRows) Next() bool {
+	for r.pos < len(r.rows) {
+		if r.rows[r.pos].Next() {
+			return true
+		}
+		if r.err = r.rows[r.pos].Err(); r.err != nil {
+			return false
+		}
+		r.pos++
+	}
+	return false
+}
+
+// NextResultSet returns false, only the first result set of each shard is read
+func (r *multiRows) NextResultSet() bool {
+	return false
+}
+
+// Err returns the error of the rows of the shard that failed
+func (r *multiRows) Err() error {
+	return r.err
+}
+
+// Columns returns the columns of the rows of the current shard
+func (r *multiRows) Columns() ([]string, error) {
+	if len(r.rows) == 0 {
+		return nil, nil
+	}
+	return r.rows[r.current()].Columns()
+}
+
+// ColumnTypes returns the column types of the rows of the current shard
+func (r *multiRows) ColumnTypes() ([]*sql.ColumnType, error) {
+	if len(r.rows) == 0 {
+		return nil, nil
+	}
+	return r.rows[r.current()].ColumnTypes()
+}
+
+// Scan copies the columns of the current row into dest
+func (r *multiRows) Scan(dest ...interface{}) error {
+	if r.pos >= len(r.rows) {
+		return sql.ErrNoRows
+	}
+	return r.rows[r.pos].Scan(dest...)
+}
+
+// Close closes the rows of every shard and returns the first error
+func (r *multiRows) Close() error {
+	var err error
+	for _, rows := range r.rows {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// current returns the index of the rows of the current shard
+func (r *multiRows) current() int {
+	if r.pos >= len(r.rows) {
+		return len(r.rows) - 1
+	}
+	return r.pos
+}
+
+// compile time check that DB implements the interface
+var _ jorm.Interface = (*DB)(nil)
@@ -0,0 +1,237 @@
+package jormshard_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormshard"
+)
+
+type order struct {
+	ID         uint
+	CustomerID uint `jorm:"shard_key"`
+	Status     string
+	Total      int
+}
+
+// open returns a db of three SQLite shards, customer c on shard c % 3, and the gorm dbs of the shards
+func open(t *testing.T) (*jormshard.DB, []*gorm.DB) {
+	t.Helper()
+	var shards []*gorm.DB
+	for i := 0; i < 3; i++ {
+		shard, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), fmt.Sprint(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { shard.Close() })
+		shards = append(shards, shard)
+	}
+
+	db := jormshard.New(jormshard.Options{Shard: func(key interface{}, n int) int { return int(key.(uint)) % n }}, shards...)
+	if err := db.AutoMigrate(&order{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return db, shards
+}
+
+// seed creates an order of each customer 1 to 6, the total of customer c is 10 * (7 - c)
+func seed(t *testing.T, db *jormshard.DB) {
+	t.Helper()
+	for c := uint(1); c <= 6; c++ {
+		if err := db.Create(&order{CustomerID: c, Status: "open", Total: 10 * (7 - int(c))}).Error(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func customers(orders []order) []uint {
+	var ids []uint
+	for _, o := range orders {
+		ids = append(ids, o.CustomerID)
+	}
+	return ids
+}
+
+func TestRouting(t *testing.T) {
+	db, shards := open(t)
+	seed(t, db)
+
+	var n int
+	if err := shards[1].Model(&order{}).Count(&n).Error; err != nil || n != 2 {
+		t.Fatalf("shard 1 has %d orders, %v", n, err)
+	}
+	if err := db.Create(&order{Status: "open"}).Error(); err != jormshard.ErrNoShardKey {
+		t.Fatalf("Create without a key: %v", err)
+	}
+
+	var orders []order
+	if err := db.Where("customer_id = ?", uint(4)).Find(&orders).Error(); err != nil || len(orders) != 1 || orders[0].CustomerID != 4 {
+		t.Fatalf("Find by key: %v %v", orders, err)
+	}
+	orders = nil
+	if err := db.Where("customer_id IN (?)", []uint{1, 2}).Find(&orders).Error(); err != nil || len(orders) != 2 {
+		t.Fatalf("Find by keys: %v %v", orders, err)
+	}
+
+	var count int64
+	if err := db.Model(&order{}).Count(&count).Error(); err != nil || count != 6 {
+		t.Fatalf("Count: %d %v", count, err)
+	}
+	var o order
+	if err := db.Where("status = ?", "none").First(&o).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Fatalf("First of none: %v", err)
+	}
+	if r := db.Model(&order{}).Where("status = ?", "open").Update("status", "closed"); r.Error() != nil || r.RowsAffected() != 6 {
+		t.Fatalf("Update: %d %v", r.RowsAffected(), r.Error())
+	}
+}
+
+func TestFindOrderAndPage(t *testing.T) {
+	db, _ := open(t)
+	seed(t, db)
+
+	var orders []order
+	if err := db.Order("total").Find(&orders).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(customers(orders)); got != "[6 5 4 3 2 1]" {
+		t.Errorf("Order(total) = %s", got)
+	}
+
+	orders = nil
+	if err := db.Order("orders.total DESC").Offset(1).Limit(3).Find(&orders).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(customers(orders)); got != "[2 3 4]" {
+		t.Errorf("Order(total DESC).Offset(1).Limit(3) = %s", got)
+	}
+
+	orders = nil
+	if err := db.Order("total, customer_id DESC").Limit(2).Find(&orders).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(customers(orders)); got != "[6 5]" {
+		t.Errorf("Order(total, customer_id DESC).Limit(2) = %s", got)
+	}
+
+	// text is ordered by the collation of its column, which only the database knows
+	if err := db.Order("status, customer_id DESC").Limit(2).Find(&orders).Error(); err != jormshard.ErrScatterOrder {
+		t.Errorf("Order(status).Limit(2) = %v", err)
+	}
+
+	var pointers []*order
+	if err := db.Order("total").Offset(4).Find(&pointers).Error(); err != nil || len(pointers) != 2 || pointers[0].CustomerID != 2 {
+		t.Errorf("Offset(4) into pointers = %v %v", pointers, err)
+	}
+
+	orders = nil
+	if err := db.Order(gorm.Expr("RANDOM()")).Find(&orders).Error(); err != jormshard.ErrScatterOrder {
+		t.Errorf("Order(Expr) = %v", err)
+	}
+	var totals []int
+	if err := db.Model(&order{}).Order("total").Pluck("total", &totals).Error(); err != jormshard.ErrScatterOrder {
+		t.Errorf("ordered Pluck = %v", err)
+	}
+	if _, err := db.Model(&order{}).Limit(1).Rows(); err != jormshard.ErrScatterOrder {
+		t.Errorf("limited Rows = %v", err)
+	}
+
+	// a single shard orders and pages itself
+	orders = nil
+	if err := db.Where("customer_id IN (?)", []uint{1, 4}).Order("total").Limit(1).Find(&orders).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(customers(orders)); got != "[4]" {
+		t.Errorf("Limit(1) on one shard = %s", got)
+	}
+}
+
+func TestFirstAndLast(t *testing.T) {
+	db, _ := open(t)
+	seed(t, db)
+
+	// every shard numbers its own orders, customers 3 and 6 have orders 1 and 2 of shard 0
+	var first, last order
+	if err := db.First(&first).Error(); err != nil || first.ID != 1 {
+		t.Errorf("First = %+v %v", first, err)
+	}
+	if err := db.Last(&last).Error(); err != nil || last.ID != 2 {
+		t.Errorf("Last = %+v %v", last, err)
+	}
+
+	first = order{}
+	if err := db.Order("total").First(&first).Error(); err != nil || first.CustomerID != 6 {
+		t.Errorf("Order(total).First = %+v %v", first, err)
+	}
+	first = order{}
+	if err := db.Order("customer_id").First(&first, "status = ?", "open").Error(); err != nil || first.CustomerID != 1 {
+		t.Errorf("First with conditions = %+v %v", first, err)
+	}
+	first = order{}
+	if err := db.Order("status").First(&first).Error(); err != jormshard.ErrScatterOrder {
+		t.Errorf("Order(status).First = %v", err)
+	}
+	first = order{}
+	if err := db.Offset(1).First(&first).Error(); err != jormshard.ErrScatterOrder {
+		t.Errorf("Offset(1).First = %v", err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	db, shards := open(t)
+	seed(t, db)
+
+	if err := db.Exec("DELETE FROM orders").Error(); err != jormshard.ErrNoShardKey {
+		t.Fatalf("Exec without a key = %v", err)
+	}
+	if r := db.ShardKey(uint(2)).Exec("DELETE FROM orders"); r.Error() != nil || r.RowsAffected() != 2 {
+		t.Fatalf("Exec with a key: %d %v", r.RowsAffected(), r.Error())
+	}
+	var n int
+	shards[2].Model(&order{}).Count(&n)
+	if n != 0 {
+		t.Fatalf("shard 2 has %d orders", n)
+	}
+	if r := db.AllShards().Exec("DELETE FROM orders"); r.Error() != nil || r.RowsAffected() != 4 {
+		t.Fatalf("Exec on every shard: %d %v", r.RowsAffected(), r.Error())
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	db, shards := open(t)
+
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		if err := tx.Create(&order{CustomerID: 3}).Error(); err != nil {
+			return err
+		}
+		return tx.Create(&order{CustomerID: 4}).Error()
+	})
+	if err != jormshard.ErrCrossShard {
+		t.Fatalf("cross-shard transaction = %v", err)
+	}
+	var n int
+	shards[0].Model(&order{}).Count(&n)
+	if n != 0 {
+		t.Fatalf("rolled back transaction created %d orders", n)
+	}
+
+	tx := db.Begin()
+	tx.Create(&order{CustomerID: 3})
+	var orders []order
+	if err := tx.Find(&orders).Error(); err != jormshard.ErrCrossShard {
+		t.Fatalf("Find on every shard in a transaction = %v", err)
+	}
+	if err := tx.Commit().Error(); err != nil {
+		t.Fatal(err)
+	}
+	shards[0].Model(&order{}).Count(&n)
+	if n != 1 {
+		t.Fatalf("committed transaction created %d orders", n)
+	}
+}
@@ -0,0 +1,257 @@
+package jormshard
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortKey is a column the records of every shard are merged by, or their primary key
+type sortKey struct {
+	column  string
+	primary bool
+	desc    bool
+}
+
+// parseOrders parses the values given to Order, false if one is not a `column [ASC|DESC]` list
+func parseOrders(values []interface{}) ([]sortKey, bool) {
+	var keys []sortKey
+	for _, value := range values {
+		list, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		for _, part := range strings.Split(list, ",") {
+			words := strings.Fields(part)
+			if len(words) == 0 || len(words) > 2 {
+				return nil, false
+			}
+
+			// the column may be quoted and prefixed with its table
+			column := words[0]
+			if i := strings.LastIndex(column, "."); i >= 0 {
+				column = column[i+1:]
+			}
+			key := sortKey{column: strings.Trim(column, "`\"")}
+			if len(words) == 2 {
+				switch strings.ToUpper(words[1]) {
+				case "ASC":
+				case "DESC":
+					key.desc = true
+				default:
+					return nil, false
+				}
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, true
+}
+
+// pageOf returns the value given to Limit or Offset, -1 if none was or it was cancelled
+func pageOf(value interface{}) int {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() >= 0 {
+			return int(v.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint())
+	}
+	return -1
+}
+
+// paged reports whether the queries of the db are ordered, limited or offset
+func (s *DB) paged() bool {
+	return len(s.orders) > 0 || pageOf(s.limit) >= 0 || pageOf(s.offset) >= 0
+}
+
+// page returns the records of a slice after offset, at most limit of them, a negative offset or limit is ignored
+func page(records reflect.Value, offset, limit int) reflect.Value {
+	if offset > 0 {
+		if offset > records.Len() {
+			offset = records.Len()
+		}
+		records = records.Slice(offset, records.Len())
+	}
+	if limit >= 0 && limit < records.Len() {
+		records = records.Slice(0, limit)
+	}
+	return records
+}
+
+// isRecordType reports whether the type is a struct or a pointer to a struct, whose fields can be sorted by
+func isRecordType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct
+}
+
+// sortRecords sorts a slice of structs or struct pointers by the keys, stably so that equal records stay in shard
+// order. NULLs sort first, as on MySQL. Text keys fail with ErrScatterOrder as their collation is not known
+func (s *DB) sortRecords(records reflect.Value, keys []sortKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values := make([][]interface{}, records.Len())
+	for i := range values {
+		record := records.Index(i)
+		if record.Kind() != reflect.Ptr {
+			record = record.Addr()
+		}
+		if record.IsNil() {
+			return ErrScatterOrder
+		}
+
+		scope := s.gormDB().NewScope(record.Interface())
+		for _, key := range keys {
+			if key.primary {
+				field := scope.PrimaryField()
+				if field == nil {
+					return ErrScatterOrder
+				}
+				values[i] = append(values[i], field.Field.Interface())
+				continue
+			}
+			field, ok := scope.FieldByName(key.column)
+			if !ok || isText(field.Field.Interface()) {
+				return ErrScatterOrder
+			}
+			values[i] = append(values[i], field.Field.Interface())
+		}
+	}
+
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		for k, key := range keys {
+			if cmp := compareValues(values[indexes[i]][k], values[indexes[j]][k]); cmp != 0 {
+				return (cmp < 0) != key.desc
+			}
+		}
+		return false
+	})
+
+	sorted := reflect.MakeSlice(records.Type(), len(indexes), len(indexes))
+	for to, from := range indexes {
+		sorted.Index(to).Set(records.Index(from))
+	}
+	reflect.Copy(records, sorted)
+	return nil
+}
+
+// compareValues compares two column values the way the database orders them, NULLs first
+func compareValues(a, b interface{}) int {
+	a, b = normalizeKey(a), normalizeKey(b)
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		}
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isInt(va) && isInt(vb):
+		return compareInts(va.Int(), vb.Int())
+	case isNumber(va) && isNumber(vb):
+		return compareFloats(toFloat(va), toFloat(vb))
+	case va.Kind() == reflect.Bool && vb.Kind() == reflect.Bool:
+		return compareBools(va.Bool(), vb.Bool())
+	}
+	return 0
+}
+
+// compareInts returns -1 if a is less than b, 1 if it is greater and 0 otherwise
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareFloats returns -1 if a is less than b, 1 if it is greater and 0 otherwise
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareBools orders false before true
+func compareBools(a, b bool) int {
+	switch {
+	case !a && b:
+		return -1
+	case a && !b:
+		return 1
+	}
+	return 0
+}
+
+// isText reports whether a column value is text or bytes, which the database orders by the collation of the column
+// rather than byte by byte
+func isText(value interface{}) bool {
+	switch normalizeKey(value).(type) {
+	case string, []byte:
+		return true
+	}
+	return false
+}
+
+// isInt reports whether the value is a signed integer
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isNumber reports whether the value is an integer or a float
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// toFloat converts a number to a float64
+func toFloat(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
@@ -0,0 +1,242 @@
+// Package jormshard routes the operations of a jorm.Interface to horizontal shards by a shard key
+//     db := jormshard.New(jormshard.Options{}, shard0, shard1, shard2)
+//     db.Create(&order)                                // runs on the shard of order.CustomerID
+//     db.Where("customer_id = ?", id).Find(&orders)    // runs on the shard of id
+//     db.Where("status = ?", "open").Find(&orders)     // runs on every shard, the results are merged
+package jormshard
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// KeyTag is the tag of the shard key field of a model
+//     type Order struct {
+//         ID         uint
+//         CustomerID uint `jorm:"shard_key"`
+//     }
+const KeyTag = "shard_key"
+
+var (
+	// ErrNoShardKey is returned by operations that must run on a single shard, such as Create, when neither the
+	// record nor the conditions have a shard key
+	ErrNoShardKey = errors.New("jormshard: no shard key")
+	// ErrCrossShard is returned by operations of a transaction that would run on another shard than the one the
+	// transaction runs on, or on more than one shard
+	ErrCrossShard = errors.New("jormshard: cross-shard transaction")
+	// ErrScatterOrder is returned by queries without a shard key that are ordered, limited or offset when their
+	// results cannot be merged in order, such as Pluck, an Order that is not a `column [ASC|DESC]` list or an Order
+	// by a text column, whose collation only the database knows
+	ErrScatterOrder = errors.New("jormshard: cannot order or page a query across shards")
+)
+
+var (
+	// andRegexp splits conditions joined by AND
+	andRegexp = regexp.MustCompile(`(?i)\s+and\s+`)
+	// orRegexp matches conditions with an OR, whose keys do not restrict the shards
+	orRegexp = regexp.MustCompile(`(?i)\bor\b`)
+	// keyConditionRegexp matches `column = ?` and `column IN (?)` conditions, the column optionally quoted and
+	// prefixed with its table
+	keyConditionRegexp = regexp.MustCompile("(?i)^[\\s(]*(?:[\\w\"`]+\\.)?[\"`]?(\\w+)[\"`]?\\s*(=|in)\\s*\\(?\\s*\\?\\s*\\)?[\\s)]*$")
+)
+
+// Options configures the routing of a sharded db
+type Options struct {
+	// Column is the shard key column of models without a field tagged `jorm:"shard_key"`, and of Table queries
+	Column string
+	// Shard returns the index of the shard of a key among n shards, defaults to HashShard
+	Shard func(key interface{}, n int) int
+}
+
+// HashShard returns the FNV-1a hash of the key's string form modulo n, so that keys of any integer type and of the
+// same value are on the same shard
+func HashShard(key interface{}, n int) int {
+	h := fnv.New32a()
+	fmt.Fprint(h, key)
+	return int(h.Sum32() % uint32(n))
+}
+
+// where is a condition given to Where or inline to a query
+type where struct {
+	query interface{}
+	args  []interface{}
+}
+
+// keyColumn returns the shard key column of the model, the column of Options if it has no tagged field
+func (s *DB) keyColumn(model interface{}) string {
+	if model != nil {
+		for _, field := range s.gormDB().NewScope(model).GetModelStruct().StructFields {
+			if isKeyField(field) {
+				return field.DBName
+			}
+		}
+	}
+	return s.opts.Column
+}
+
+// isKeyField reports whether the field is tagged as the shard key
+func isKeyField(field *gorm.StructField) bool {
+	for _, tag := range strings.Split(field.Tag.Get("jorm"), ";") {
+		if strings.TrimSpace(tag) == KeyTag {
+			return true
+		}
+	}
+	return false
+}
+
+// valueKey returns the shard key of a struct record, false if it is not a struct or its key is blank
+func (s *DB) valueKey(value interface{}) (interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	column := s.keyColumn(value)
+	if column == "" {
+		return nil, false
+	}
+	for _, field := range s.gormDB().NewScope(value).Fields() {
+		if field.DBName == column && !field.IsBlank {
+			return field.Field.Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// conditionKeys returns the shard keys a condition restricts the column to, false if it does not restrict it
+func (s *DB) conditionKeys(column string, w where) ([]interface{}, bool) {
+	switch query := w.query.(type) {
+	case string:
+		if orRegexp.MatchString(query) {
+			return nil, false
+		}
+		arg := 0
+		for _, part := range andRegexp.Split(query, -1) {
+			match := keyConditionRegexp.FindStringSubmatch(part)
+			if match != nil && match[1] == column && arg < len(w.args) {
+				return expandKeys(w.args[arg], strings.EqualFold(match[2], "in"))
+			}
+			arg += strings.Count(part, "?")
+		}
+		return nil, false
+	case map[string]interface{}:
+		if key, ok := query[column]; ok {
+			return expandKeys(key, true)
+		}
+		return nil, false
+	}
+
+	if reflect.Indirect(reflect.ValueOf(w.query)).Kind() == reflect.Struct {
+		if key, ok := s.valueKey(w.query); ok {
+			return []interface{}{key}, true
+		}
+	}
+	return nil, false
+}
+
+// expandKeys returns the key, or the elements of a slice key when list is true
+func expandKeys(key interface{}, list bool) ([]interface{}, bool) {
+	v := reflect.ValueOf(key)
+	if !list || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() == reflect.Uint8 {
+		return []interface{}{key}, true
+	}
+	keys := make([]interface{}, v.Len())
+	for i := range keys {
+		keys[i] = v.Index(i).Interface()
+	}
+	return keys, true
+}
+
+// normalizeKey dereferences pointers and driver.Valuers so that a key routes like its value
+func normalizeKey(key interface{}) interface{} {
+	v := reflect.ValueOf(key)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			return value
+		}
+	}
+	return v.Interface()
+}
+
+// targets returns the indexes of the shards an operation on value with the inline conditions runs on, in order,
+// every shard when there is no shard key
+func (s *DB) targets(value interface{}, inline []interface{}) []int {
+	if s.keys != nil {
+		return s.shardsOf(s.keys)
+	}
+	if key, ok := s.valueKey(value); ok {
+		return s.shardsOf([]interface{}{key})
+	}
+	if key, ok := s.valueKey(s.model); ok {
+		return s.shardsOf([]interface{}{key})
+	}
+
+	model := value
+	if model == nil {
+		model = s.model
+	}
+	column := s.keyColumn(model)
+
+	wheres := s.wheres
+	if len(inline) > 0 {
+		wheres = append(append([]where(nil), wheres...), where{query: inline[0], args: inline[1:]})
+	}
+
+	var shards map[int]bool
+	if column != "" && !s.scatter {
+		for _, w := range wheres {
+			keys, ok := s.conditionKeys(column, w)
+			if !ok {
+				continue
+			}
+			// conditions are joined by AND so the shards are those of every condition
+			next := map[int]bool{}
+			for _, shard := range s.shardsOf(keys) {
+				if shards == nil || shards[shard] {
+					next[shard] = true
+				}
+			}
+			shards = next
+		}
+	}
+
+	if shards == nil {
+		return s.allShards()
+	}
+	var targets []int
+	for i := range s.shards {
+		if shards[i] {
+			targets = append(targets, i)
+		}
+	}
+	return targets
+}
+
+// shardsOf returns the indexes of the shards of the keys, in order and without duplicates
+func (s *DB) shardsOf(keys []interface{}) []int {
+	found := map[int]bool{}
+	for _, key := range keys {
+		found[s.opts.Shard(normalizeKey(key), len(s.shards))] = true
+	}
+	var shards []int
+	for i := range s.shards {
+		if found[i] {
+			shards = append(shards, i)
+		}
+	}
+	return shards
+}
@@ -0,0 +1,376 @@
+package jormtest
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// condition is a single `column op value` comparison, all the conditions of a query must match
+type condition struct {
+	column string
+	op     string
+	value  interface{}
+}
+
+// order sorts records by a column
+type order struct {
+	column string
+	desc   bool
+}
+
+var (
+	andRegexp       = regexp.MustCompile(`(?i)\s+and\s+`)
+	conditionRegexp = regexp.MustCompile(`(?i)^\s*([\w.` + "`" + `"]+)\s*(=|<>|!=|<=|>=|<|>|not\s+in|in|not\s+like|like|is\s+not\s+null|is\s+null)\s*(\(\s*\?\s*\)|\?)?\s*$`)
+	primaryRegexp   = regexp.MustCompile(`^\s*\d+\s*$`)
+	spaceRegexp     = regexp.MustCompile(`\s+`)
+)
+
+// parseConditions parses the arguments of Where into conditions, query can be a struct, a map, a primary key or a
+// string of `column op ?` comparisons joined by AND, an error describes why anything else is not supported
+func parseConditions(m *model, query interface{}, args ...interface{}) ([]condition, error) {
+	value := reflect.Indirect(reflect.ValueOf(query))
+
+	switch {
+	case !value.IsValid():
+		return nil, fmt.Errorf("nil conditions")
+	case value.Kind() == reflect.String && !primaryRegexp.MatchString(value.String()):
+		return parseStringConditions(value.String(), args)
+	case len(args) > 0:
+		return nil, fmt.Errorf("arguments %v for non string conditions %v", args, query)
+	case value.Kind() == reflect.Map:
+		var conds []condition
+		for _, key := range value.MapKeys() {
+			if key.Kind() != reflect.String {
+				return nil, fmt.Errorf("map conditions with non string key %v", key)
+			}
+			conds = append(conds, condition{column: key.String(), op: "=", value: value.MapIndex(key).Interface()})
+		}
+		return conds, nil
+	case value.Kind() == reflect.Struct && value.Type() != timeType:
+		var conds []condition
+		for _, f := range modelOf(value.Type()).fields {
+			if fv := value.FieldByIndex(f.index); !isZero(fv) {
+				conds = append(conds, condition{column: f.column, op: "=", value: fv.Interface()})
+			}
+		}
+		return conds, nil
+	case m == nil || m.primary == nil:
+		return nil, fmt.Errorf("primary key conditions %v without a model with a primary key", query)
+	case value.Kind() == reflect.Slice:
+		return []condition{{column: m.primary.column, op: "IN", value: value.Interface()}}, nil
+	}
+	return []condition{{column: m.primary.column, op: "=", value: value.Interface()}}, nil
+}
+
+// parseStringConditions parses `column op ?` comparisons joined by AND
+func parseStringConditions(query string, args []interface{}) ([]condition, error) {
+	var conds []condition
+	for _, part := range andRegexp.Split(strings.TrimSpace(query), -1) {
+		match := conditionRegexp.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("condition %q", part)
+		}
+
+		cond := condition{column: match[1], op: strings.ToUpper(spaceRegexp.ReplaceAllString(match[2], " "))}
+		if strings.HasPrefix(cond.op, "IS ") {
+			if match[3] != "" {
+				return nil, fmt.Errorf("condition %q", part)
+			}
+			conds = append(conds, cond)
+			continue
+		}
+
+		if match[3] == "" || len(args) == 0 {
+			return nil, fmt.Errorf("condition %q without an argument", part)
+		}
+		cond.value, args = args[0], args[1:]
+		conds = append(conds, cond)
+	}
+
+	if len(args) > 0 {
+		return nil, fmt.Errorf("unused arguments %v for %q", args, query)
+	}
+	return conds, nil
+}
+
+// parseOrder parses a comma separated list of `column [ASC|DESC]`
+func parseOrder(value string) ([]order, error) {
+	var orders []order
+	for _, part := range strings.Split(value, ",") {
+		words := strings.Fields(part)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, fmt.Errorf("order %q", value)
+		}
+
+		o := order{column: words[0]}
+		if len(words) == 2 {
+			switch strings.ToUpper(words[1]) {
+			case "ASC":
+			case "DESC":
+				o.desc = true
+			default:
+				return nil, fmt.Errorf("order %q", value)
+			}
+		}
+		orders = append(orders, o)
+	}
+	return orders, nil
+}
+
+// matches reports whether the record matches the condition
+func (c condition) matches(m *model, record reflect.Value) (bool, error) {
+	f := m.field(c.column)
+	if f == nil {
+		return false, fmt.Errorf("unknown column %q of %v", c.column, m.typ)
+	}
+	actual := record.FieldByIndex(f.index).Interface()
+
+	switch c.op {
+	case "IS NULL", "IS NOT NULL":
+		null := indirect(actual) == nil
+		return null == (c.op == "IS NULL"), nil
+	case "IN", "NOT IN":
+		values := reflect.ValueOf(c.value)
+		if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+			return false, fmt.Errorf("%s with non slice argument %v", c.op, c.value)
+		}
+		for i := 0; i < values.Len(); i++ {
+			cmp, err := compare(actual, values.Index(i).Interface())
+			if err != nil {
+				return false, err
+			}
+			if cmp == 0 {
+				return c.op == "IN", nil
+			}
+		}
+		return c.op == "NOT IN", nil
+	case "LIKE", "NOT LIKE":
+		pattern, ok := indirect(c.value).(string)
+		if !ok {
+			return false, fmt.Errorf("%s with non string argument %v", c.op, c.value)
+		}
+		s, _ := indirect(actual).(string)
+		return like(pattern).MatchString(s) == (c.op == "LIKE"), nil
+	}
+
+	cmp, err := compare(actual, c.value)
+	if err != nil {
+		return false, err
+	}
+	switch c.op {
+	case "=":
+		return cmp == 0, nil
+	case "<>", "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+// like converts a LIKE pattern to a case insensitive regular expression
+func like(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// indirect dereferences pointers and driver.Valuers down to a plain value, nil for NULL
+func indirect(value interface{}) interface{} {
+	for value != nil {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil
+		}
+
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if _, again := v.(driver.Valuer); err != nil || again {
+				return v
+			}
+			value = v
+			continue
+		}
+
+		if rv.Kind() != reflect.Ptr {
+			return value
+		}
+		value = rv.Elem().Interface()
+	}
+	return nil
+}
+
+// compare returns -1, 0 or 1 if a is less than, equal to or greater than b, NULL sorts first
+func compare(a, b interface{}) (int, error) {
+	a, b = indirect(a), indirect(b)
+	switch {
+	case a == nil && b == nil:
+		return 0, nil
+	case a == nil:
+		return -1, nil
+	case b == nil:
+		return 1, nil
+	}
+
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			switch {
+			case at.Before(bt):
+				return -1, nil
+			case at.After(bt):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+
+	if an, ok := number(a); ok {
+		if bn, ok := number(b); ok {
+			return an.Cmp(bn), nil
+		}
+	}
+
+	if as, ok := text(a); ok {
+		if bs, ok := text(b); ok {
+			return strings.Compare(as, bs), nil
+		}
+	}
+
+	if ab, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok {
+			switch {
+			case ab == bb:
+				return 0, nil
+			case bb:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+}
+
+// number returns the value as an exact big.Float if it is a number
+func number(value interface{}) (*big.Float, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return new(big.Float).SetFloat64(v.Float()), true
+	}
+	return nil, false
+}
+
+// text returns the value as a string if it is a string or []byte
+func text(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String(), true
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return string(v.Bytes()), true
+	}
+	return "", false
+}
+
+// assign sets the field to the value, converting it and parsing text the way database/sql would
+func assign(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(v)
+		return nil
+	case field.Kind() == reflect.Ptr:
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+			v = v.Elem()
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := assign(elem.Elem(), v.Interface()); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		return assign(field, v.Elem().Interface())
+	case v.Type().ConvertibleTo(field.Type()) && (v.Kind() == reflect.String) == (field.Kind() == reflect.String):
+		field.Set(v.Convert(field.Type()))
+		return nil
+	}
+
+	if s, ok := text(value); ok {
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(s)
+			return nil
+		case reflect.Slice:
+			if field.Type().Elem().Kind() == reflect.Uint8 {
+				field.SetBytes([]byte(s))
+				return nil
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(s, 10, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("cannot assign %q to %v: %v", s, field.Type(), err)
+			}
+			field.SetInt(i)
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, err := strconv.ParseUint(s, 10, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("cannot assign %q to %v: %v", s, field.Type(), err)
+			}
+			field.SetUint(u)
+			return nil
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(s, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("cannot assign %q to %v: %v", s, field.Type(), err)
+			}
+			field.SetFloat(f)
+			return nil
+		}
+	}
+
+	if scanner, ok := field.Addr().Interface().(interface{ Scan(interface{}) error }); ok {
+		return scanner.Scan(indirect(value))
+	}
+	return fmt.Errorf("cannot assign %T to %v", value, field.Type())
+}
@@ -0,0 +1,1367 @@
+package jormtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+)
+
+// Fake is an in-memory implementation of jorm.Interface for tests, it stores records per model type and supports the
+// common subset of gorm: Where with a struct, a map, a primary key or `column = ?` style comparisons joined by AND,
+// Order, Limit, Offset, Unscoped, locking reads, First, Take, Last, Find, Count, Pluck, FirstOrInit, FirstOrCreate, Create, Save,
+// Update(s), UpdateColumn(s), Delete, soft deletes and transactions.
+// Anything else fails the test and returns an error wrapping ErrUnsupported, so that a test can never pass because
+// part of a query was silently ignored
+type Fake struct {
+	t                 testing.TB
+	store             *store
+	ctx               context.Context
+	model             reflect.Type
+	value             interface{}
+	wheres            []where
+	orders            []order
+	limit             int
+	offset            int
+	unscoped          bool
+	locking           bool
+	blockGlobalUpdate bool
+	settings          map[string]interface{}
+	txDepth           int
+	snapshot          map[reflect.Type][]reflect.Value
+	result            interface{}
+	err               error
+	rowsAffected      int64
+}
+
+// ErrUnsupported is wrapped by the errors of the operations the Fake does not support, which also fail the test
+var ErrUnsupported = errors.New("jormtest: not supported by Fake")
+
+// where is a call to Where, it is parsed once the model of the query is known
+type where struct {
+	query interface{}
+	args  []interface{}
+}
+
+// NewFake returns an empty Fake which fails the given test when it is used in a way it does not support
+func NewFake(t testing.TB) *Fake {
+	return &Fake{t: t, store: newStore(), limit: -1, offset: -1, settings: map[string]interface{}{}}
+}
+
+// clone returns a copy of the fake sharing its records
+func (f *Fake) clone() *Fake {
+	c := *f
+	c.wheres = append([]where(nil), f.wheres...)
+	c.orders = append([]order(nil), f.orders...)
+	c.rowsAffected = 0
+	return &c
+}
+
+// unsupported fails the test and returns an error wrapping ErrUnsupported, it does not stop the test so that it can be
+// called from any goroutine
+func (f *Fake) unsupported(format string, args ...interface{}) error {
+	f.t.Helper()
+	err := fmt.Errorf("%w: "+format, append([]interface{}{ErrUnsupported}, args...)...)
+	f.t.Errorf("%v", err)
+	return err
+}
+
+// fail returns a clone of the fake holding the error of an unsupported operation
+func (f *Fake) fail(format string, args ...interface{}) jorm.Interface {
+	f.t.Helper()
+	c := f.clone()
+	c.AddError(f.unsupported(format, args...))
+	return c
+}
+
+// done returns a clone holding the outcome of an operation
+func (f *Fake) done(result interface{}, err error, rowsAffected int64) jorm.Interface {
+	c := f.clone()
+	c.result = result
+	c.rowsAffected = rowsAffected
+	c.AddError(err)
+	return c
+}
+
+// modelFor returns the model of the struct type of value, or of the Model of the chain if value is nil
+func (f *Fake) modelFor(value interface{}) (*model, error) {
+	f.t.Helper()
+	typ := f.model
+	if value != nil {
+		typ = reflect.TypeOf(value)
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+			typ = typ.Elem()
+		}
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, f.unsupported("operations without a model, pass a pointer to a struct or call Model (got %T)", value)
+	}
+	return modelOf(typ), nil
+}
+
+// conditions parses the conditions of the chain, with where as extra inline conditions
+func (f *Fake) conditions(m *model, inline ...interface{}) ([]condition, error) {
+	f.t.Helper()
+	wheres := f.wheres
+	if len(inline) > 0 {
+		wheres = append(wheres[:len(wheres):len(wheres)], where{query: inline[0], args: inline[1:]})
+	}
+
+	var conds []condition
+	for _, w := range wheres {
+		c, err := parseConditions(m, w.query, w.args...)
+		if err != nil {
+			return nil, f.unsupported("%v", err)
+		}
+		conds = append(conds, c...)
+	}
+	return conds, nil
+}
+
+// primaryCondition returns the condition on the primary key of value, if it has one, gorm adds it to the statements
+// of a value with a primary key
+func (f *Fake) primaryCondition(m *model, value interface{}) []condition {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if m.primary == nil || !rv.IsValid() || rv.Type() != m.typ {
+		return nil
+	}
+	if pk := rv.FieldByIndex(m.primary.index); !isZero(pk) {
+		return []condition{{column: m.primary.column, op: "=", value: pk.Interface()}}
+	}
+	return nil
+}
+
+// match returns the indexes of the stored records of the model that match the conditions, the store must be locked
+func (f *Fake) match(m *model, conds []condition) ([]int, error) {
+	f.t.Helper()
+	var indexes []int
+	for i, record := range f.store.tables[m.typ] {
+		if m.deletedAt != nil && !f.unscoped && isDeleted(record.FieldByIndex(m.deletedAt.index)) {
+			continue
+		}
+
+		matches := true
+		for _, cond := range conds {
+			ok, err := cond.matches(m, record)
+			if err != nil {
+				return nil, f.unsupported("%v", err)
+			}
+			if !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// isDeleted reports whether the DeletedAt field of a record is set
+func isDeleted(deletedAt reflect.Value) bool {
+	switch v := indirect(deletedAt.Interface()).(type) {
+	case nil:
+		return false
+	case time.Time:
+		return !v.IsZero()
+	}
+	return true
+}
+
+// find returns copies of the records of the model that match the conditions, sorted, offset and limited
+func (f *Fake) find(m *model, conds []condition, orders []order, limit int) ([]reflect.Value, error) {
+	f.t.Helper()
+	for _, o := range orders {
+		if m.field(o.column) == nil {
+			return nil, f.unsupported("ordering by unknown column %q of %v", o.column, m.typ)
+		}
+	}
+
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	indexes, err := f.match(m, conds)
+	if err != nil {
+		return nil, err
+	}
+	var records []reflect.Value
+	for _, i := range indexes {
+		records = append(records, copyRecord(f.store.tables[m.typ][i]))
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, o := range orders {
+			fi := m.field(o.column)
+			cmp, err := compare(records[i].FieldByIndex(fi.index).Interface(), records[j].FieldByIndex(fi.index).Interface())
+			if err != nil || cmp == 0 {
+				continue
+			}
+			return (cmp < 0) != o.desc
+		}
+		return false
+	})
+
+	if f.offset > 0 {
+		if f.offset >= len(records) {
+			return nil, nil
+		}
+		records = records[f.offset:]
+	}
+	if f.limit >= 0 && f.limit < len(records) {
+		records = records[:f.limit]
+	}
+	if limit >= 0 && limit < len(records) {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// primaryOrder returns the orders of the chain followed by the primary key
+func (f *Fake) primaryOrder(m *model, desc bool) []order {
+	orders := append([]order(nil), f.orders...)
+	if m.primary != nil {
+		orders = append(orders, order{column: m.primary.column, desc: desc})
+	}
+	return orders
+}
+
+// first finds the first record in the given order into out, restricted to the primary key of out if it has one
+func (f *Fake) first(out interface{}, where []interface{}, orders func(m *model) []order) jorm.Interface {
+	f.t.Helper()
+	if err := f.lockError(); err != nil {
+		return f.done(out, err, 0)
+	}
+	m, err := f.modelFor(out)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+	conds, err := f.conditions(m, where...)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+	records, err := f.find(m, append(conds, f.primaryCondition(m, out)...), orders(m), 1)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+	if len(records) == 0 {
+		return f.done(out, gorm.ErrRecordNotFound, 0)
+	}
+
+	if err := f.set(out, records[0]); err != nil {
+		return f.done(out, err, 0)
+	}
+	return f.done(out, nil, 1)
+}
+
+// set sets out, a pointer to a struct or a pointer to a struct pointer, to the record
+func (f *Fake) set(out interface{}, record reflect.Value) error {
+	f.t.Helper()
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return f.unsupported("non pointer destination %T", out)
+	}
+	rv = rv.Elem()
+	if rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(record.Type()))
+		rv = rv.Elem()
+	}
+	rv.Set(record)
+	return nil
+}
+
+// attrs converts the arguments of Update(s) to values by field
+func (f *Fake) attrs(m *model, values interface{}) (map[*field]interface{}, error) {
+	f.t.Helper()
+	attrs := map[*field]interface{}{}
+	rv := reflect.Indirect(reflect.ValueOf(values))
+
+	switch rv.Kind() {
+	case reflect.Map:
+		for _, key := range rv.MapKeys() {
+			fd := m.field(fmt.Sprint(key.Interface()))
+			if fd == nil {
+				return nil, f.unsupported("updating unknown column %v of %v", key.Interface(), m.typ)
+			}
+			attrs[fd] = rv.MapIndex(key).Interface()
+		}
+	case reflect.Struct:
+		for _, fd := range modelOf(rv.Type()).fields {
+			if fv := rv.FieldByIndex(fd.index); !isZero(fv) {
+				if mf := m.field(fd.column); mf != nil {
+					attrs[mf] = fv.Interface()
+				}
+			}
+		}
+	default:
+		return nil, f.unsupported("updating with %T", values)
+	}
+	return attrs, nil
+}
+
+// update applies the attributes to the records matching the chain and to the model
+func (f *Fake) update(values interface{}, touch bool) jorm.Interface {
+	f.t.Helper()
+	m, err := f.modelFor(nil)
+	if err != nil {
+		return f.done(f.value, err, 0)
+	}
+	attrs, err := f.attrs(m, values)
+	if err != nil {
+		return f.done(f.value, err, 0)
+	}
+	if touch && m.updatedAt != nil {
+		if _, ok := attrs[m.updatedAt]; !ok {
+			attrs[m.updatedAt] = time.Now()
+		}
+	}
+
+	conds, err := f.conditions(m)
+	if err != nil {
+		return f.done(f.value, err, 0)
+	}
+	conds = append(conds, f.primaryCondition(m, f.value)...)
+	if len(conds) == 0 && f.blockGlobalUpdate {
+		return f.done(f.value, errors.New("missing WHERE clause while updating"), 0)
+	}
+
+	// the values are checked against a copy first so that a record is never partially updated
+	check := reflect.New(m.typ).Elem()
+	for fd, v := range attrs {
+		if err := assign(check.FieldByIndex(fd.index), v); err != nil {
+			return f.done(f.value, f.unsupported("%v", err), 0)
+		}
+	}
+	apply := func(record reflect.Value) {
+		for fd := range attrs {
+			record.FieldByIndex(fd.index).Set(check.FieldByIndex(fd.index))
+		}
+	}
+
+	f.store.mu.Lock()
+	indexes, err := f.match(m, conds)
+	for _, i := range indexes {
+		apply(f.store.tables[m.typ][i])
+	}
+	f.store.mu.Unlock()
+	if err != nil {
+		return f.done(f.value, err, 0)
+	}
+
+	if rv := reflect.ValueOf(f.value); rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Type() == m.typ {
+		apply(rv.Elem())
+	}
+	return f.done(f.value, nil, int64(len(indexes)))
+}
+
+// WithContext returns a clone of the fake, the context is not used
+func (f *Fake) WithContext(ctx context.Context) jorm.Interface {
+	c := f.clone()
+	c.ctx = ctx
+	return c
+}
+
+// Value returns the value of the last operation
+func (f *Fake) Value() interface{} {
+	return f.result
+}
+
+// Error returns the error of the chain
+func (f *Fake) Error() error {
+	return f.err
+}
+
+// RowsAffected returns how many records the last operation found or changed
+func (f *Fake) RowsAffected() int64 {
+	return f.rowsAffected
+}
+
+// GetGormDB is not supported as there is no gorm DB, it fails the test and returns nil
+func (f *Fake) GetGormDB() *gorm.DB {
+	f.t.Helper()
+	f.unsupported("GetGormDB")
+	return nil
+}
+
+// New returns a clone of the fake without conditions
+func (f *Fake) New() jorm.Interface {
+	c := f.clone()
+	c.model, c.value, c.result = nil, nil, nil
+	c.wheres, c.orders = nil, nil
+	c.limit, c.offset = -1, -1
+	c.unscoped = false
+	return c
+}
+
+// Close does nothing
+func (f *Fake) Close() error {
+	return nil
+}
+
+// DB returns nil as there is no database
+func (f *Fake) DB() *sql.DB {
+	return nil
+}
+
+// CommonDB is not supported as there is no database, it fails the test and returns nil
+func (f *Fake) CommonDB() jorm.SQLCommon {
+	f.t.Helper()
+	f.unsupported("CommonDB")
+	return nil
+}
+
+// Dialect is not supported as there is no database, it fails the test and returns nil
+func (f *Fake) Dialect() jorm.Dialect {
+	f.t.Helper()
+	f.unsupported("Dialect")
+	return nil
+}
+
+// Callback is not supported as callbacks are never run, it fails the test and returns nil
+func (f *Fake) Callback() jorm.Callback {
+	f.t.Helper()
+	f.unsupported("Callback")
+	return nil
+}
+
+// SetLogger does nothing
+func (f *Fake) SetLogger(log mysql.Logger) {}
+
+// WithLogger returns a clone of the fake, nothing is logged
+func (f *Fake) WithLogger(logger jorm.Logger) jorm.Interface {
+	return f.clone()
+}
+
+// LogMode returns a clone of the fake, nothing is logged
+func (f *Fake) LogMode(enable bool) jorm.Interface {
+	return f.clone()
+}
+
+// UsePrimary returns a clone of the fake, there are no replicas
+func (f *Fake) UsePrimary() jorm.Interface {
+	return f.clone()
+}
+
+// WithoutTenantScope returns a clone of the fake, its records are not restricted to tenants
+func (f *Fake) WithoutTenantScope() jorm.Interface {
+	return f.clone()
+}
+
+// BlockGlobalUpdate if true, updates and deletes without conditions return an error like they would with gorm
+func (f *Fake) BlockGlobalUpdate(enable bool) jorm.Interface {
+	c := f.clone()
+	c.blockGlobalUpdate = enable
+	return c
+}
+
+// HasBlockGlobalUpdate return state of block
+func (f *Fake) HasBlockGlobalUpdate() bool {
+	return f.blockGlobalUpdate
+}
+
+// SingularTable does nothing as records are stored by type
+func (f *Fake) SingularTable(enable bool) {}
+
+// Where adds conditions, a struct, a map, a primary key or `column op ?` comparisons joined by AND are supported
+// where op is one of =, <>, !=, <, <=, >, >=, IN, NOT IN, LIKE, NOT LIKE, IS NULL or IS NOT NULL
+func (f *Fake) Where(query interface{}, args ...interface{}) jorm.Interface {
+	c := f.clone()
+	c.wheres = append(c.wheres, where{query: query, args: args})
+	return c
+}
+
+// Or is not supported
+func (f *Fake) Or(query interface{}, args ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Or")
+}
+
+// Not is not supported
+func (f *Fake) Not(query interface{}, args ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Not")
+}
+
+// Limit specify the number of records to be retrieved, -1 cancels it
+func (f *Fake) Limit(limit interface{}) jorm.Interface {
+	f.t.Helper()
+	n, err := f.integer("Limit", limit)
+	if err != nil {
+		return f.done(nil, err, 0)
+	}
+	c := f.clone()
+	c.limit = n
+	return c
+}
+
+// Offset specify the number of records to skip before starting to return the records, -1 cancels it
+func (f *Fake) Offset(offset interface{}) jorm.Interface {
+	f.t.Helper()
+	n, err := f.integer("Offset", offset)
+	if err != nil {
+		return f.done(nil, err, 0)
+	}
+	c := f.clone()
+	c.offset = n
+	return c
+}
+
+// integer converts the argument of Limit or Offset to an int
+func (f *Fake) integer(name string, value interface{}) (int, error) {
+	f.t.Helper()
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), nil
+	}
+	return -1, f.unsupported("%s with %T", name, value)
+}
+
+// Order specify order of the records, only `column [ASC|DESC]` lists are supported
+func (f *Fake) Order(value interface{}, reorder ...bool) jorm.Interface {
+	f.t.Helper()
+	s, ok := value.(string)
+	if !ok {
+		return f.fail("Order with %T", value)
+	}
+	orders, err := parseOrder(s)
+	if err != nil {
+		return f.fail("%v", err)
+	}
+
+	c := f.clone()
+	if len(reorder) > 0 && reorder[0] {
+		c.orders = nil
+	}
+	c.orders = append(c.orders, orders...)
+	return c
+}
+
+// Select is not supported
+func (f *Fake) Select(query interface{}, args ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Select")
+}
+
+// Omit is not supported
+func (f *Fake) Omit(columns ...string) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Omit")
+}
+
+// Group is not supported
+func (f *Fake) Group(query string) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Group")
+}
+
+// Having is not supported
+func (f *Fake) Having(query interface{}, values ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Having")
+}
+
+// Joins is not supported
+func (f *Fake) Joins(query string, args ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Joins")
+}
+
+// Scopes is not supported as scopes work on a *gorm.DB
+func (f *Fake) Scopes(funcs ...func(*gorm.DB) *gorm.DB) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Scopes")
+}
+
+// Unscoped includes soft deleted records and makes Delete remove records rather than soft delete them
+func (f *Fake) Unscoped() jorm.Interface {
+	c := f.clone()
+	c.unscoped = true
+	return c
+}
+
+// Assign is not supported
+func (f *Fake) Assign(attrs ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Assign")
+}
+
+// Attrs is not supported
+func (f *Fake) Attrs(attrs ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Attrs")
+}
+
+// First find first record that match given conditions, order by primary key
+func (f *Fake) First(out interface{}, where ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.first(out, where, func(m *model) []order { return f.primaryOrder(m, false) })
+}
+
+// Take return a record that match given conditions, in the order the records were stored
+func (f *Fake) Take(out interface{}, where ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.first(out, where, func(m *model) []order { return f.orders })
+}
+
+// Last find last record that match given conditions, order by primary key
+func (f *Fake) Last(out interface{}, where ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.first(out, where, func(m *model) []order { return f.primaryOrder(m, true) })
+}
+
+// Find find records that match given conditions into a pointer to a slice of structs or struct pointers
+func (f *Fake) Find(out interface{}, where ...interface{}) jorm.Interface {
+	f.t.Helper()
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return f.fail("Find with non pointer destination %T", out)
+	}
+	if rv.Elem().Kind() != reflect.Slice {
+		return f.Take(out, where...)
+	}
+	if err := f.lockError(); err != nil {
+		return f.done(out, err, 0)
+	}
+
+	m, err := f.modelFor(out)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+	conds, err := f.conditions(m, where...)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+	records, err := f.find(m, conds, f.orders, -1)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+
+	slice := reflect.MakeSlice(rv.Elem().Type(), 0, len(records))
+	isPtr := rv.Elem().Type().Elem().Kind() == reflect.Ptr
+	for _, record := range records {
+		if isPtr {
+			record = record.Addr()
+		}
+		slice = reflect.Append(slice, record)
+	}
+	rv.Elem().Set(slice)
+	return f.done(out, nil, int64(len(records)))
+}
+
+// Scan is not supported
+func (f *Fake) Scan(dest interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Scan")
+}
+
+// Row is not supported, the row holds the error
+func (f *Fake) Row() jorm.Row {
+	f.t.Helper()
+	return errRow{err: f.unsupported("Row")}
+}
+
+// Rows is not supported
+func (f *Fake) Rows() (jorm.Rows, error) {
+	f.t.Helper()
+	return nil, f.unsupported("Rows")
+}
+
+// ScanRows scan the current row of rows, such as Rows from NewRows, into the columns of a struct or into a single value
+func (f *Fake) ScanRows(rows jorm.Rows, result interface{}) error {
+	f.t.Helper()
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return f.unsupported("ScanRows into non pointer destination %T", result)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	elem := rv.Elem()
+	dest := make([]interface{}, len(columns))
+	switch {
+	case elem.Kind() == reflect.Struct && elem.Type() != timeType:
+		m := modelOf(elem.Type())
+		for i, column := range columns {
+			if fd := m.field(column); fd != nil {
+				dest[i] = elem.FieldByIndex(fd.index).Addr().Interface()
+			} else {
+				dest[i] = new(interface{})
+			}
+		}
+	case len(columns) == 1:
+		dest[0] = result
+	default:
+		return f.unsupported("ScanRows of %d columns into %T", len(columns), result)
+	}
+	return rows.Scan(dest...)
+}
+
+// Pluck query a single column of the Model into a pointer to a slice
+func (f *Fake) Pluck(column string, value interface{}) jorm.Interface {
+	f.t.Helper()
+	if err := f.lockError(); err != nil {
+		return f.done(value, err, 0)
+	}
+	m, err := f.modelFor(nil)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	fd := m.field(column)
+	if fd == nil {
+		return f.done(value, f.unsupported("Pluck of unknown column %q of %v", column, m.typ), 0)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return f.done(value, f.unsupported("Pluck into %T", value), 0)
+	}
+
+	records, err := f.modelRecords(m, f.orders)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	slice := reflect.MakeSlice(rv.Elem().Type(), 0, len(records))
+	for _, record := range records {
+		elem := reflect.New(slice.Type().Elem()).Elem()
+		if err := assign(elem, record.FieldByIndex(fd.index).Interface()); err != nil {
+			return f.done(value, f.unsupported("%v", err), 0)
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	rv.Elem().Set(slice)
+	return f.done(value, nil, int64(len(records)))
+}
+
+// Count get how many records of the Model match the conditions into a pointer to an integer
+func (f *Fake) Count(value interface{}) jorm.Interface {
+	f.t.Helper()
+	if err := f.lockError(); err != nil {
+		return f.done(value, err, 0)
+	}
+	m, err := f.modelFor(nil)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	c := f.clone()
+	c.limit, c.offset = -1, -1
+	records, err := c.modelRecords(m, nil)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return f.done(value, f.unsupported("Count into %T", value), 0)
+	}
+	if err := assign(rv.Elem(), len(records)); err != nil {
+		return f.done(value, f.unsupported("%v", err), 0)
+	}
+	return f.done(value, nil, 1)
+}
+
+// modelRecords returns the records matching the conditions of the chain and the primary key of its Model, like the
+// queries of gorm on a Model with a primary key
+func (f *Fake) modelRecords(m *model, orders []order) ([]reflect.Value, error) {
+	f.t.Helper()
+	conds, err := f.conditions(m)
+	if err != nil {
+		return nil, err
+	}
+	return f.find(m, append(conds, f.primaryCondition(m, f.value)...), orders, -1)
+}
+
+// Related is not supported
+func (f *Fake) Related(value interface{}, foreignKeys ...string) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Related")
+}
+
+// FirstOrInit find first matched record or initialize out with the equality conditions
+func (f *Fake) FirstOrInit(out interface{}, where ...interface{}) jorm.Interface {
+	f.t.Helper()
+	found := f.First(out, where...)
+	if !found.RecordNotFound() {
+		return found
+	}
+
+	m, err := f.modelFor(out)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+	conds, err := f.conditions(m, where...)
+	if err != nil {
+		return f.done(out, err, 0)
+	}
+	record := reflect.Indirect(reflect.ValueOf(out))
+	for _, cond := range conds {
+		if fd := m.field(cond.column); fd != nil && cond.op == "=" {
+			if err := assign(record.FieldByIndex(fd.index), cond.value); err != nil {
+				return f.done(out, f.unsupported("%v", err), 0)
+			}
+		}
+	}
+	return f.done(out, nil, 0)
+}
+
+// FirstOrCreate find first matched record or create a new one with the equality conditions
+func (f *Fake) FirstOrCreate(out interface{}, where ...interface{}) jorm.Interface {
+	f.t.Helper()
+	found := f.First(out, where...)
+	if !found.RecordNotFound() {
+		return found
+	}
+	initialized := f.FirstOrInit(out, where...)
+	if err := initialized.Error(); err != nil {
+		return initialized
+	}
+	return initialized.Create(out)
+}
+
+// Update update attributes of the Model, with a column and a value or with a map or struct
+func (f *Fake) Update(attrs ...interface{}) jorm.Interface {
+	f.t.Helper()
+	switch len(attrs) {
+	case 1:
+		return f.update(attrs[0], true)
+	case 2:
+		return f.update(map[string]interface{}{fmt.Sprint(attrs[0]): attrs[1]}, true)
+	}
+	return f.fail("Update with %d arguments", len(attrs))
+}
+
+// Updates update attributes of the Model with a map or the non blank fields of a struct
+func (f *Fake) Updates(values interface{}, ignoreProtectedAttrs ...bool) jorm.Interface {
+	f.t.Helper()
+	return f.update(values, true)
+}
+
+// UpdateColumn update attributes like Update without touching UpdatedAt
+func (f *Fake) UpdateColumn(attrs ...interface{}) jorm.Interface {
+	f.t.Helper()
+	switch len(attrs) {
+	case 1:
+		return f.update(attrs[0], false)
+	case 2:
+		return f.update(map[string]interface{}{fmt.Sprint(attrs[0]): attrs[1]}, false)
+	}
+	return f.fail("UpdateColumn with %d arguments", len(attrs))
+}
+
+// UpdateColumns update attributes like Updates without touching UpdatedAt
+func (f *Fake) UpdateColumns(values interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.update(values, false)
+}
+
+// Save replace the stored record with the same primary key, or insert it if it doesn't have a primary key
+func (f *Fake) Save(value interface{}) jorm.Interface {
+	f.t.Helper()
+	m, err := f.modelFor(value)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	if len(f.primaryCondition(m, value)) == 0 {
+		return f.Create(value)
+	}
+
+	record := reflect.Indirect(reflect.ValueOf(value))
+	if m.updatedAt != nil {
+		assign(record.FieldByIndex(m.updatedAt.index), time.Now())
+	}
+
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	c := f.clone()
+	c.unscoped = true
+	indexes, err := c.match(m, f.primaryCondition(m, value))
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	if len(indexes) > 0 {
+		f.store.tables[m.typ][indexes[0]] = copyRecord(record)
+	} else {
+		f.store.tables[m.typ] = append(f.store.tables[m.typ], copyRecord(record))
+	}
+	return f.done(value, nil, 1)
+}
+
+// Create insert the value, a pointer to a struct, assigning an auto increment primary key if it doesn't have one
+func (f *Fake) Create(value interface{}) jorm.Interface {
+	f.t.Helper()
+	m, err := f.modelFor(value)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	record := reflect.ValueOf(value)
+	if record.Kind() != reflect.Ptr || record.Elem().Kind() != reflect.Struct {
+		return f.done(value, f.unsupported("Create with %T", value), 0)
+	}
+	record = record.Elem()
+
+	now := time.Now()
+	for _, fd := range []*field{m.createdAt, m.updatedAt} {
+		if fd != nil && isZero(record.FieldByIndex(fd.index)) {
+			assign(record.FieldByIndex(fd.index), now)
+		}
+	}
+
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	if m.primary != nil {
+		pk := record.FieldByIndex(m.primary.index)
+		c := f.clone()
+		c.unscoped = true
+		switch {
+		case isZero(pk):
+			var max int64
+			for _, stored := range f.store.tables[m.typ] {
+				if n, ok := number(stored.FieldByIndex(m.primary.index).Interface()); ok {
+					if i, _ := n.Int64(); i > max {
+						max = i
+					}
+				}
+			}
+			if err := assign(pk, max+1); err != nil {
+				return f.done(value, f.unsupported("generating primary key: %v", err), 0)
+			}
+		case c.hasRecord(m, value):
+			err := fmt.Errorf("jormtest: duplicate primary key %v for %v", pk.Interface(), m.typ)
+			return f.done(value, &jorm.Error{Kind: jorm.ErrDuplicateKey, Constraint: "PRIMARY", Err: err}, 0)
+		}
+	}
+
+	f.store.tables[m.typ] = append(f.store.tables[m.typ], copyRecord(record))
+	return f.done(value, nil, 1)
+}
+
+// hasRecord reports whether a record has the primary key of value, the store must be locked
+func (f *Fake) hasRecord(m *model, value interface{}) bool {
+	indexes, _ := f.match(m, f.primaryCondition(m, value))
+	return len(indexes) > 0
+}
+
+// Delete delete value match given conditions, if the value has primary key, then will including the primary key as
+// condition.  Models with a DeletedAt field are soft deleted unless Unscoped is used
+func (f *Fake) Delete(value interface{}, where ...interface{}) jorm.Interface {
+	f.t.Helper()
+	m, err := f.modelFor(value)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	conds, err := f.conditions(m, where...)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	conds = append(conds, f.primaryCondition(m, value)...)
+	if len(conds) == 0 && f.blockGlobalUpdate {
+		return f.done(value, errors.New("missing WHERE clause while deleting"), 0)
+	}
+
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	indexes, err := f.match(m, conds)
+	if err != nil {
+		return f.done(value, err, 0)
+	}
+	if m.deletedAt != nil && !f.unscoped {
+		now := time.Now()
+		for _, i := range indexes {
+			assign(f.store.tables[m.typ][i].FieldByIndex(m.deletedAt.index), now)
+		}
+		return f.done(value, nil, int64(len(indexes)))
+	}
+
+	deleted := map[int]bool{}
+	for _, i := range indexes {
+		deleted[i] = true
+	}
+	var kept []reflect.Value
+	for i, record := range f.store.tables[m.typ] {
+		if !deleted[i] {
+			kept = append(kept, record)
+		}
+	}
+	f.store.tables[m.typ] = kept
+	return f.done(value, nil, int64(len(indexes)))
+}
+
+// Raw is not supported
+func (f *Fake) Raw(sql string, values ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Raw")
+}
+
+// Exec is not supported
+func (f *Fake) Exec(sql string, values ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Exec")
+}
+
+// Model specify the model of Update(s), Count and Pluck, a value with a primary key restricts Update(s) to it
+func (f *Fake) Model(value interface{}) jorm.Interface {
+	f.t.Helper()
+	m, err := f.modelFor(value)
+	if err != nil {
+		return f.done(nil, err, 0)
+	}
+	c := f.clone()
+	c.model = m.typ
+	c.value = value
+	return c
+}
+
+// Table is not supported as records are stored by type
+func (f *Fake) Table(name string) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Table")
+}
+
+// Debug returns a clone of the fake, nothing is logged
+func (f *Fake) Debug() jorm.Interface {
+	return f.clone()
+}
+
+// Begin begin a transaction, the records are restored if it is rolled back
+func (f *Fake) Begin() jorm.Interface {
+	c := f.clone()
+	c.txDepth++
+	c.snapshot = f.store.snapshot()
+	return c
+}
+
+// Commit commit a transaction
+func (f *Fake) Commit() jorm.Interface {
+	if f.txDepth == 0 {
+		return f.done(nil, gorm.ErrInvalidTransaction, 0)
+	}
+	c := f.clone()
+	c.txDepth--
+	c.snapshot = nil
+	return c
+}
+
+// Rollback rollback a transaction, restoring the records stored when it began
+func (f *Fake) Rollback() jorm.Interface {
+	if f.txDepth == 0 {
+		return f.done(nil, gorm.ErrInvalidTransaction, 0)
+	}
+	f.store.restore(f.snapshot)
+	c := f.clone()
+	c.txDepth--
+	c.snapshot = nil
+	return c
+}
+
+// Transaction run fn in a transaction, committing it if fn returns nil and rolling it back if fn returns an error or
+// panics, a panic is re-raised after the rollback
+func (f *Fake) Transaction(ctx context.Context, fn func(tx jorm.Interface) error) error {
+	tx := f.WithContext(ctx).Begin()
+	if err := tx.Error(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error()
+}
+
+// TxDepth returns how many transactions deep the fake is
+func (f *Fake) TxDepth() int {
+	return f.txDepth
+}
+
+// SavepointName returns the name a *jorm.DB would use for the savepoint of the current nested transaction
+func (f *Fake) SavepointName() string {
+	if f.txDepth < 2 {
+		return ""
+	}
+	return fmt.Sprintf("jorm_savepoint_%d", f.txDepth-1)
+}
+
+// NewRecord check if value's primary key is blank
+func (f *Fake) NewRecord(value interface{}) bool {
+	f.t.Helper()
+	m, err := f.modelFor(value)
+	return err == nil && len(f.primaryCondition(m, value)) == 0
+}
+
+// RecordNotFound check if returning ErrRecordNotFound error
+func (f *Fake) RecordNotFound() bool {
+	for _, err := range f.GetErrors() {
+		if err == gorm.ErrRecordNotFound {
+			return true
+		}
+	}
+	return false
+}
+
+// ToSQL is not supported as there is no SQL
+func (f *Fake) ToSQL(fn func(tx jorm.Interface) jorm.Interface) (string, []interface{}, error) {
+	f.t.Helper()
+	return "", nil, f.unsupported("ToSQL")
+}
+
+// CreateTable does nothing as records are stored by type
+func (f *Fake) CreateTable(models ...interface{}) jorm.Interface {
+	return f.AutoMigrate(models...)
+}
+
+// DropTable delete every record of the models
+func (f *Fake) DropTable(values ...interface{}) jorm.Interface {
+	f.t.Helper()
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	for _, value := range values {
+		m, err := f.modelFor(value)
+		if err != nil {
+			return f.done(nil, err, 0)
+		}
+		delete(f.store.tables, m.typ)
+	}
+	return f.clone()
+}
+
+// DropTableIfExists delete every record of the models
+func (f *Fake) DropTableIfExists(values ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.DropTable(values...)
+}
+
+// HasTable check if the model has been migrated or has any records
+func (f *Fake) HasTable(value interface{}) bool {
+	f.t.Helper()
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	m, err := f.modelFor(value)
+	if err != nil {
+		return false
+	}
+	_, ok := f.store.tables[m.typ]
+	return ok
+}
+
+// AutoMigrate makes HasTable true for the models
+func (f *Fake) AutoMigrate(values ...interface{}) jorm.Interface {
+	f.t.Helper()
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	for _, value := range values {
+		m, err := f.modelFor(value)
+		if err != nil {
+			return f.done(nil, err, 0)
+		}
+		if _, ok := f.store.tables[m.typ]; !ok {
+			f.store.tables[m.typ] = nil
+		}
+	}
+	return f.clone()
+}
+
+// ModifyColumn does nothing as there is no schema
+func (f *Fake) ModifyColumn(column string, typ string) jorm.Interface {
+	return f.clone()
+}
+
+// DropColumn does nothing as there is no schema
+func (f *Fake) DropColumn(column string) jorm.Interface {
+	return f.clone()
+}
+
+// AddIndex does nothing as there is no schema
+func (f *Fake) AddIndex(indexName string, columns ...string) jorm.Interface {
+	return f.clone()
+}
+
+// AddUniqueIndex does nothing as there is no schema, uniqueness is not enforced
+func (f *Fake) AddUniqueIndex(indexName string, columns ...string) jorm.Interface {
+	return f.clone()
+}
+
+// RemoveIndex does nothing as there is no schema
+func (f *Fake) RemoveIndex(indexName string) jorm.Interface {
+	return f.clone()
+}
+
+// AddForeignKey does nothing as there is no schema, foreign keys are not enforced
+func (f *Fake) AddForeignKey(field string, dest string, onDelete string, onUpdate string) jorm.Interface {
+	return f.clone()
+}
+
+// RemoveForeignKey does nothing as there is no schema
+func (f *Fake) RemoveForeignKey(field string, dest string) jorm.Interface {
+	return f.clone()
+}
+
+// Association is not supported, the association holds the error
+func (f *Fake) Association(column string) jorm.Association {
+	f.t.Helper()
+	return errAssociation{err: f.unsupported("Association")}
+}
+
+// Preload is not supported
+func (f *Fake) Preload(column string, conditions ...interface{}) jorm.Interface {
+	f.t.Helper()
+	return f.fail("Preload")
+}
+
+// ForUpdate returns a clone of the fake whose queries must run in a transaction, records are not locked
+func (f *Fake) ForUpdate() jorm.Interface {
+	return f.lock()
+}
+
+// ForShare returns a clone of the fake whose queries must run in a transaction, records are not locked
+func (f *Fake) ForShare() jorm.Interface {
+	return f.lock()
+}
+
+// SkipLocked returns a clone of the fake whose queries must run in a transaction, no record is ever locked
+func (f *Fake) SkipLocked() jorm.Interface {
+	return f.lock()
+}
+
+// NoWait returns a clone of the fake whose queries must run in a transaction, no record is ever locked
+func (f *Fake) NoWait() jorm.Interface {
+	return f.lock()
+}
+
+// lock returns a clone of the fake whose queries are locking reads
+func (f *Fake) lock() *Fake {
+	c := f.clone()
+	c.locking = true
+	return c
+}
+
+// lockError returns ErrNotInTransaction for a locking read outside of a transaction, like a *jorm.DB
+func (f *Fake) lockError() error {
+	if f.locking && f.txDepth == 0 {
+		return jorm.ErrNotInTransaction
+	}
+	return nil
+}
+
+// Set set setting by name, will clone a new fake, and update its setting
+func (f *Fake) Set(name string, value interface{}) jorm.Interface {
+	c := f.clone()
+	c.settings = make(map[string]interface{}, len(f.settings)+1)
+	for k, v := range f.settings {
+		c.settings[k] = v
+	}
+	c.settings[name] = value
+	return c
+}
+
+// InstantSet instant set setting, will affect current fake
+func (f *Fake) InstantSet(name string, value interface{}) jorm.Interface {
+	f.settings[name] = value
+	return f
+}
+
+// Get get setting by name
+func (f *Fake) Get(name string) (value interface{}, ok bool) {
+	value, ok = f.settings[name]
+	return
+}
+
+// SetJoinTableHandler is not supported
+func (f *Fake) SetJoinTableHandler(source interface{}, column string, handler gorm.JoinTableHandlerInterface) {
+	f.t.Helper()
+	f.unsupported("SetJoinTableHandler")
+}
+
+// AddError add error to the fake
+func (f *Fake) AddError(err error) error {
+	if err != nil {
+		errs := gorm.Errors(f.GetErrors()).Add(err)
+		if len(errs) > 1 {
+			err = errs
+		}
+		f.err = err
+	}
+	return f.err
+}
+
+// GetErrors get happened errors from the fake
+func (f *Fake) GetErrors() []error {
+	if errs, ok := f.err.(gorm.Errors); ok {
+		return errs
+	}
+	if f.err != nil {
+		return []error{f.err}
+	}
+	return []error{}
+}
+
+// errAssociation is an association holding the error of an unsupported operation
+type errAssociation struct {
+	err error
+}
+
+// Find does nothing
+func (a errAssociation) Find(value interface{}) jorm.Association {
+	return a
+}
+
+// Append does nothing
+func (a errAssociation) Append(values ...interface{}) jorm.Association {
+	return a
+}
+
+// Replace does nothing
+func (a errAssociation) Replace(values ...interface{}) jorm.Association {
+	return a
+}
+
+// Delete does nothing
+func (a errAssociation) Delete(values ...interface{}) jorm.Association {
+	return a
+}
+
+// Clear does nothing
+func (a errAssociation) Clear() jorm.Association {
+	return a
+}
+
+// Count returns 0
+func (a errAssociation) Count() int {
+	return 0
+}
+
+// Error returns the error of the association
+func (a errAssociation) Error() error {
+	return a.err
+}
+
+// compile time check that Fake implements the interface
+var _ jorm.Interface = (*Fake)(nil)
@@ -0,0 +1,179 @@
+package jormtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormtest"
+)
+
+type user struct {
+	ID        uint
+	Name      string
+	Age       int
+	Email     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// recordingTB records the errors reported to it instead of failing the test
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+// seedFake creates bob, alice, carol and dave aged 20 to 23 with IDs 1 to 4
+func seedFake(t *testing.T, db jorm.Interface) {
+	t.Helper()
+	for i, name := range []string{"bob", "alice", "carol", "dave"} {
+		u := user{Name: name, Age: 20 + i}
+		if err := db.Create(&u).Error(); err != nil || u.ID != uint(i+1) {
+			t.Fatalf("Create = %+v %v", u, err)
+		}
+	}
+}
+
+func TestFakeQueries(t *testing.T) {
+	db := jormtest.NewFake(t)
+	seedFake(t, db)
+
+	var users []user
+	if err := db.Where("age >= ? AND name <> ?", 21, "carol").Order("age desc").Limit(5).Find(&users).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Name != "dave" || users[1].Name != "alice" {
+		t.Errorf("Find = %+v", users)
+	}
+
+	var u user
+	if !db.Where(map[string]interface{}{"name": "zed"}).First(&u).RecordNotFound() {
+		t.Error("First of a missing name found a record")
+	}
+	if err := db.First(&u, 3).Error(); err != nil || u.Name != "carol" {
+		t.Errorf("First(3) = %+v %v", u, err)
+	}
+
+	var count int64
+	if err := db.Model(&user{}).Where(&user{Name: "bob"}).Count(&count).Error(); err != nil || count != 1 {
+		t.Errorf("Count by struct = %d %v", count, err)
+	}
+	if err := db.Model(&user{}).Where("name IN (?)", []string{"bob", "alice"}).Count(&count).Error(); err != nil || count != 2 {
+		t.Errorf("Count by IN = %d %v", count, err)
+	}
+	if err := db.Model(&user{}).Where("name LIKE ?", "A%").Count(&count).Error(); err != nil || count != 1 {
+		t.Errorf("Count by LIKE = %d %v", count, err)
+	}
+
+	var pointers []*user
+	if err := db.Where("email IS NULL").Order("id").Offset(1).Find(&pointers).Error(); err != nil || len(pointers) != 3 || pointers[0].Name != "alice" {
+		t.Errorf("Find with Offset = %v %v", pointers, err)
+	}
+}
+
+func TestFakePrimaryKeyOfDestination(t *testing.T) {
+	db := jormtest.NewFake(t)
+	seedFake(t, db)
+
+	// like gorm, the primary key of a loaded record restricts the query
+	u := user{ID: 3}
+	if err := db.Last(&u).Error(); err != nil || u.Name != "carol" {
+		t.Errorf("Last of a loaded record = %+v %v", u, err)
+	}
+	u = user{ID: 2}
+	if err := db.Where("age > ?", 21).First(&u).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Errorf("First of a loaded record not matching = %+v %v", u, err)
+	}
+	var users []user
+	if err := db.Find(&users).Error(); err != nil || len(users) != 4 {
+		t.Errorf("Find = %d records %v", len(users), err)
+	}
+
+	u = user{}
+	if err := db.Last(&u).Error(); err != nil || u.Name != "dave" {
+		t.Errorf("Last = %+v %v", u, err)
+	}
+}
+
+func TestFakeWrites(t *testing.T) {
+	db := jormtest.NewFake(t)
+	seedFake(t, db)
+
+	u := user{ID: 4}
+	if err := db.First(&u).Error(); err != nil {
+		t.Fatal(err)
+	}
+	r := db.Model(&u).Update("name", "david")
+	if r.Error() != nil || r.RowsAffected() != 1 || u.Name != "david" {
+		t.Errorf("Update = %d %+v %v", r.RowsAffected(), u, r.Error())
+	}
+	if err := db.Delete(&user{}, "name = ?", "bob").Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	if err := db.Model(&user{}).Order("id").Pluck("name", &names).Error(); err != nil || strings.Join(names, ",") != "alice,carol,david" {
+		t.Errorf("Pluck = %v %v", names, err)
+	}
+	var count int64
+	if err := db.Unscoped().Model(&user{}).Count(&count).Error(); err != nil || count != 4 {
+		t.Errorf("Unscoped Count = %d %v", count, err)
+	}
+
+	frank := user{}
+	if err := db.FirstOrCreate(&frank, user{Name: "frank"}).Error(); err != nil || frank.ID == 0 || frank.Name != "frank" {
+		t.Errorf("FirstOrCreate = %+v %v", frank, err)
+	}
+}
+
+func TestFakeTransaction(t *testing.T) {
+	db := jormtest.NewFake(t)
+
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		if err := tx.Create(&user{Name: "eve"}).Error(); err != nil {
+			return err
+		}
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("Transaction returned no error")
+	}
+	var count int64
+	if err := db.Model(&user{}).Count(&count).Error(); err != nil || count != 0 {
+		t.Errorf("Count after rollback = %d %v", count, err)
+	}
+}
+
+func TestFakeUnsupported(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	db := jormtest.NewFake(tb)
+	seedFake(t, db)
+
+	var users []user
+	err := db.Joins("JOIN emails ON emails.user_id = users.id").Find(&users).Error()
+	if !errors.Is(err, jormtest.ErrUnsupported) {
+		t.Errorf("Joins = %v", err)
+	}
+	if err := db.Order("nickname").Find(&users).Error(); !errors.Is(err, jormtest.ErrUnsupported) {
+		t.Errorf("Order by an unknown column = %v", err)
+	}
+	if len(tb.errors) != 2 {
+		t.Errorf("reported %q, want an error per unsupported operation", tb.errors)
+	}
+
+	// the fake keeps working after an unsupported operation
+	if err := db.Find(&users).Error(); err != nil || len(users) != 4 {
+		t.Errorf("Find = %d records %v", len(users), err)
+	}
+}
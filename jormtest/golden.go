@@ -0,0 +1,148 @@
+package jormtest
+
+import (
+	"database/sql/driver"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jloom6/jorm"
+)
+
+// UpdateGolden makes Golden rewrite the golden files instead of comparing statements to them, it is set by the
+// -jormtest.update flag and tests with an update flag of their own can set it from theirs
+//     var update = flag.Bool("update", false, "rewrite golden files")
+//
+//     func TestMain(m *testing.M) {
+//         flag.Parse()
+//         jormtest.UpdateGolden = *update
+//         os.Exit(m.Run())
+//     }
+var UpdateGolden bool
+
+func init() {
+	// the flag is namespaced so that it cannot clash with an -update flag of the test binary
+	flag.BoolVar(&UpdateGolden, "jormtest.update", false, "rewrite the golden files of jormtest.Golden")
+}
+
+// goldenHookName is the name the statement hook of Golden is added under
+const goldenHookName = "jormtest:golden"
+
+var (
+	whitespaceRegexp  = regexp.MustCompile(`\s+`)
+	placeholderRegexp = regexp.MustCompile(`\$\d+|@p\d+`)
+	uuidRegexp        = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	timeRegexp        = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}(\.\d+)?( ?(Z|[+-]\d{2}:?\d{2}))?`)
+)
+
+// Golden runs fn with a clone of the db that records every statement it runs, and compares them to the golden file
+// testdata/<name>.golden. Running the tests with -jormtest.update rewrites the golden files instead, see UpdateGolden.
+// Statements are normalized with NormalizeSQL and NormalizeArg so that the files do not depend on the dialect's
+// placeholders, timestamps or generated IDs. A dry run db builds the statements without a database
+//     jormtest.Golden(t, db.DryRun(), "active_users", func(db jorm.Interface) {
+//         NewUserRepository(db).Active()
+//     })
+func Golden(t testing.TB, db *jorm.DB, name string, fn func(db jorm.Interface)) {
+	t.Helper()
+
+	var (
+		mu  sync.Mutex
+		got strings.Builder
+	)
+	fn(db.OnStatement(goldenHookName, func(stmt *jorm.Statement) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeStatement(&got, stmt)
+	}))
+
+	path := filepath.Join("testdata", name+".golden")
+	if UpdateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("jormtest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got.String()), 0644); err != nil {
+			t.Fatalf("jormtest: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("jormtest: %v, run the test with -jormtest.update to create it", err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("jormtest: statements differ from %s, run the test with -jormtest.update to rewrite it\ngot:\n%s\nwant:\n%s",
+			path, got.String(), want)
+	}
+}
+
+// writeStatement writes a statement as it is stored in golden files
+func writeStatement(b *strings.Builder, stmt *jorm.Statement) {
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.TrimSpace("-- " + stmt.Operation + " " + stmt.Table))
+	b.WriteString("\n")
+	b.WriteString(NormalizeSQL(stmt.SQL))
+	b.WriteString("\n")
+	if len(stmt.Vars) > 0 {
+		args := make([]string, len(stmt.Vars))
+		for i, v := range stmt.Vars {
+			args[i] = NormalizeArg(v)
+		}
+		b.WriteString("-- args: " + strings.Join(args, ", ") + "\n")
+	}
+}
+
+// NormalizeSQL collapses whitespace, replaces numbered placeholders such as $1 and @p1 with ? and timestamps and
+// UUIDs with <time> and <uuid>
+func NormalizeSQL(sql string) string {
+	sql = whitespaceRegexp.ReplaceAllString(strings.TrimSpace(sql), " ")
+	sql = placeholderRegexp.ReplaceAllString(sql, "?")
+	return normalizeValues(sql)
+}
+
+// NormalizeArg formats an argument of a statement, after dereferencing pointers and driver.Valuers, with NULL for
+// nil, <time> for times and timestamps or UUIDs within strings replaced by <time> and <uuid>
+func NormalizeArg(arg interface{}) string {
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Kind() == reflect.Ptr {
+		return "NULL"
+	}
+	value := v.Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		if dv, err := valuer.Value(); err == nil {
+			if dv == nil {
+				return "NULL"
+			}
+			value = dv
+		}
+	}
+
+	switch value := value.(type) {
+	case time.Time:
+		return "<time>"
+	case string:
+		return strconv.Quote(normalizeValues(value))
+	case []byte:
+		return strconv.Quote(normalizeValues(string(value)))
+	}
+	return fmt.Sprint(value)
+}
+
+// normalizeValues replaces timestamps and UUIDs within a string
+func normalizeValues(s string) string {
+	s = timeRegexp.ReplaceAllString(s, "<time>")
+	return uuidRegexp.ReplaceAllString(s, "<uuid>")
+}
@@ -0,0 +1,123 @@
+package jormtest_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormtest"
+)
+
+// openSQLite returns a db of a SQLite database in a temporary directory with the users table
+func openSQLite(t *testing.T) *jorm.DB {
+	t.Helper()
+	g, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "jormtest.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Close() })
+
+	db := jorm.NewDB(g)
+	if err := db.AutoMigrate(&user{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// renameBob runs the statements of the golden tests
+func renameBob(db jorm.Interface) {
+	db.Create(&user{Name: "bob", Age: 20})
+	db.Where("name = ? AND created_at < ?", "bob", time.Now()).Order("name").Find(&[]user{})
+	tx := db.Begin()
+	tx.Exec("UPDATE users   SET\n name = $1 WHERE id = $2", "robert", 1)
+	tx.Commit()
+}
+
+func TestGolden(t *testing.T) {
+	jormtest.Golden(t, openSQLite(t).DryRun(), "rename_bob", renameBob)
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	jormtest.Golden(tb, openSQLite(t).DryRun(), "rename_bob", func(db jorm.Interface) {
+		db.Create(&user{Name: "alice"})
+	})
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "differ from testdata/rename_bob.golden") {
+		t.Errorf("errors = %q", tb.errors)
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	jormtest.UpdateGolden = true
+	t.Cleanup(func() { jormtest.UpdateGolden = false })
+
+	db := openSQLite(t).DryRun()
+	jormtest.Golden(t, db, "rename_bob", renameBob)
+	got, err := os.ReadFile(filepath.Join("testdata", "rename_bob.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join(wd, "testdata", "rename_bob.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("rewrote\n%s\nwant\n%s", got, want)
+	}
+
+	jormtest.UpdateGolden = false
+	jormtest.Golden(t, db, "rename_bob", renameBob)
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := map[string]string{
+		"SELECT *\n\tFROM  users ":                           "SELECT * FROM users",
+		"UPDATE users SET name = $1 WHERE id = $2":           "UPDATE users SET name = ? WHERE id = ?",
+		"SELECT * FROM users WHERE id = @p1":                 "SELECT * FROM users WHERE id = ?",
+		"WHERE created_at < '2020-01-02 03:04:05.123+00:00'": "WHERE created_at < '<time>'",
+		"WHERE ref = '123E4567-E89B-12D3-A456-426614174000'": "WHERE ref = '<uuid>'",
+	}
+	for sql, want := range tests {
+		if got := jormtest.NormalizeSQL(sql); got != want {
+			t.Errorf("NormalizeSQL(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
+
+func TestNormalizeArg(t *testing.T) {
+	name := "bob"
+	tests := []struct {
+		arg  interface{}
+		want string
+	}{
+		{nil, "NULL"},
+		{(*string)(nil), "NULL"},
+		{&name, `"bob"`},
+		{[]byte("bob"), `"bob"`},
+		{42, "42"},
+		{time.Now(), "<time>"},
+		{"at 2020-01-02T03:04:05Z", `"at <time>"`},
+		{"123e4567-e89b-12d3-a456-426614174000", `"<uuid>"`},
+		{sql.NullString{}, "NULL"},
+		{sql.NullInt64{Int64: 4, Valid: true}, "4"},
+	}
+	for _, test := range tests {
+		if got := jormtest.NormalizeArg(test.arg); got != test.want {
+			t.Errorf("NormalizeArg(%#v) = %q, want %q", test.arg, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package jormtest
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// field is a column of a model
+type field struct {
+	name    string
+	column  string
+	index   []int
+	primary bool
+}
+
+// model describes the columns of a struct the same way gorm does
+type model struct {
+	typ       reflect.Type
+	fields    []*field
+	byName    map[string]*field
+	primary   *field
+	createdAt *field
+	updatedAt *field
+	deletedAt *field
+}
+
+var models sync.Map
+
+// modelOf returns the model of the given struct type
+func modelOf(typ reflect.Type) *model {
+	if m, ok := models.Load(typ); ok {
+		return m.(*model)
+	}
+
+	m := &model{typ: typ, byName: map[string]*field{}}
+	m.addFields(typ, nil)
+	for _, f := range m.fields {
+		if m.primary == nil && (f.primary || f.name == "ID") {
+			m.primary = f
+		}
+	}
+	m.createdAt = m.byName["created_at"]
+	m.updatedAt = m.byName["updated_at"]
+	m.deletedAt = m.byName["deleted_at"]
+
+	models.Store(typ, m)
+	return m
+}
+
+// addFields adds the column fields of the given struct type, flattening anonymous and embedded structs
+func (m *model) addFields(typ reflect.Type, index []int) {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		tags := parseTag(sf.Tag.Get("gorm"))
+		if _, ok := tags["-"]; ok {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if _, embedded := tags["EMBEDDED"]; (sf.Anonymous || embedded) && ft.Kind() == reflect.Struct && ft != timeType {
+			m.addFields(ft, fieldIndex)
+			continue
+		}
+		if !isColumnType(sf.Type) {
+			continue
+		}
+
+		f := &field{name: sf.Name, column: gorm.ToDBName(sf.Name), index: fieldIndex}
+		if column, ok := tags["COLUMN"]; ok && column != "" {
+			f.column = column
+		}
+		if _, ok := tags["PRIMARY_KEY"]; ok {
+			f.primary = true
+		}
+
+		m.fields = append(m.fields, f)
+		m.byName[f.column] = f
+		m.byName[f.name] = f
+	}
+}
+
+// field returns the field for the given column or field name, ignoring any table prefix and quotes
+func (m *model) field(name string) *field {
+	name = strings.Trim(name, "`\"' ")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = strings.Trim(name[i+1:], "`\"")
+	}
+	return m.byName[name]
+}
+
+// isColumnType reports whether a field of the given type is stored in a column rather than being an association
+func isColumnType(typ reflect.Type) bool {
+	if typ.Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()) || reflect.PtrTo(typ).Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()) {
+		return true
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		return typ == timeType
+	case reflect.Slice:
+		return typ.Elem().Kind() == reflect.Uint8
+	case reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return false
+	}
+	return true
+}
+
+// parseTag parses a `gorm:"..."` tag into upper cased keys and their values
+func parseTag(tag string) map[string]string {
+	settings := map[string]string{}
+	for _, part := range strings.Split(tag, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, ":", 2)
+		key := strings.TrimSpace(strings.ToUpper(kv[0]))
+		if len(kv) == 2 {
+			settings[key] = kv[1]
+		} else {
+			settings[key] = key
+		}
+	}
+	return settings
+}
+
+// isZero reports whether the value is the zero value of its type
+func isZero(v reflect.Value) bool {
+	return !v.IsValid() || v.IsZero()
+}
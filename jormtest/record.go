@@ -0,0 +1,681 @@
+package jormtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+)
+
+// errReplayMismatch is returned for the statements of a replay once a statement differed from the recording
+var errReplayMismatch = errors.New("jormtest: statement differs from the recording")
+
+// sentinels are the jorm errors a recorded error can be classified as
+var sentinels = []error{
+	jorm.ErrDuplicateKey,
+	jorm.ErrForeignKeyViolation,
+	jorm.ErrNotNullViolation,
+	jorm.ErrCheckViolation,
+	jorm.ErrDataTooLong,
+	jorm.ErrDeadlock,
+	jorm.ErrLockTimeout,
+	jorm.ErrConnection,
+}
+
+// Record returns a clone of the db that runs its statements on the database and records them, with their args,
+// result sets, rows affected and errors, to the file at path once the test ends. Replay serves the recording
+// without a database. Statements are recorded in the order they run so the test should run them one at a time,
+// migrations and the dialect's own queries are not recorded
+//     db := jormtest.Record(t, realDB, "testdata/signup.json")
+func Record(t testing.TB, db *jorm.DB, path string) *jorm.DB {
+	t.Helper()
+
+	conn := db.DB()
+	if conn == nil {
+		t.Fatalf("jormtest: Record requires a db with a *sql.DB connection")
+	}
+	r := &recorder{conn: conn, dialect: db.Dialect().GetName()}
+	recordDB := sql.OpenDB(r)
+
+	t.Cleanup(func() {
+		recordDB.Close()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		b, err := json.MarshalIndent(recording{Dialect: r.dialect, Statements: r.statements}, "", "  ")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, append(b, '\n'), 0644)
+		}
+		if err != nil {
+			t.Errorf("jormtest: writing recording %s: %v", path, err)
+		}
+	})
+	return db.WithConnection(recordDB)
+}
+
+// Replay returns a db that serves the statements recorded by Record at path in order, without a database.
+// A statement that differs from the next recorded one fails the test with a diff of the two and fails every
+// statement after it, and the test fails if it ends before every recorded statement was replayed
+//     db := jormtest.Replay(t, "testdata/signup.json")
+func Replay(t testing.TB, path string) *jorm.DB {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("jormtest: %v, record it with Record", err)
+	}
+	var rec recording
+	if err := json.Unmarshal(b, &rec); err != nil {
+		t.Fatalf("jormtest: reading recording %s: %v", path, err)
+	}
+
+	r := &replayer{t: t, path: path, statements: rec.Statements}
+	db, err := gorm.Open(rec.Dialect, sql.OpenDB(r))
+	if err != nil {
+		t.Fatalf("jormtest: %v", err)
+	}
+
+	t.Cleanup(func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if !r.failed && r.pos < len(r.statements) {
+			next := r.statements[r.pos]
+			t.Errorf("jormtest: %d of the statements recorded in %s were not replayed, the next is\n%s",
+				len(r.statements)-r.pos, path, strings.Join(statementLines(next.SQL, next.normalizedArgs()), "\n"))
+		}
+	})
+	return jorm.NewDB(db)
+}
+
+// recording is the content of the file written by Record
+type recording struct {
+	Dialect    string              `json:"dialect"`
+	Statements []recordedStatement `json:"statements"`
+}
+
+// recordedStatement is a statement and its outcome, transactions are recorded as BEGIN, COMMIT and ROLLBACK
+type recordedStatement struct {
+	SQL          string            `json:"sql"`
+	Query        bool              `json:"query,omitempty"`
+	Args         []recordedValue   `json:"args,omitempty"`
+	Columns      []string          `json:"columns,omitempty"`
+	Rows         [][]recordedValue `json:"rows,omitempty"`
+	RowsAffected int64             `json:"rows_affected,omitempty"`
+	LastInsertID int64             `json:"last_insert_id,omitempty"`
+	Error        *recordedError    `json:"error,omitempty"`
+}
+
+// normalizedArgs returns the args of the statement as NormalizeArg formats them
+func (st recordedStatement) normalizedArgs() []string {
+	args := make([]string, len(st.Args))
+	for i, arg := range st.Args {
+		args[i] = NormalizeArg(arg.value)
+	}
+	return args
+}
+
+// recordedError is the error of a statement with the jorm error it is classified as
+type recordedError struct {
+	Message    string `json:"message"`
+	Kind       string `json:"kind,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// newRecordedError records an error classified for the dialect, nil for no error
+func newRecordedError(dialect string, err error) *recordedError {
+	if err == nil {
+		return nil
+	}
+	recorded := &recordedError{Message: err.Error()}
+	var classified *jorm.Error
+	if errors.As(jorm.Classify(dialect, err), &classified) {
+		recorded.Kind = classified.Kind.Error()
+		recorded.Constraint = classified.Constraint
+	}
+	return recorded
+}
+
+// err returns the recorded error, classified as it was when it was recorded
+func (e *recordedError) err() error {
+	err := errors.New(e.Message)
+	for _, sentinel := range sentinels {
+		if sentinel.Error() == e.Kind {
+			return &jorm.Error{Kind: sentinel, Constraint: e.Constraint, Err: err}
+		}
+	}
+	return err
+}
+
+// recordedValue is an argument or column value, stored as an object keyed by its type so it is read back as the
+// same type
+type recordedValue struct {
+	value driver.Value
+}
+
+// MarshalJSON encodes the value keyed by its type
+func (v recordedValue) MarshalJSON() ([]byte, error) {
+	switch value := v.value.(type) {
+	case nil:
+		return []byte("null"), nil
+	case int64:
+		return json.Marshal(map[string]int64{"int64": value})
+	case float64:
+		return json.Marshal(map[string]float64{"float64": value})
+	case bool:
+		return json.Marshal(map[string]bool{"bool": value})
+	case string:
+		return json.Marshal(map[string]string{"string": value})
+	case []byte:
+		// bytes that are text are stored as text to keep recordings readable
+		if utf8.Valid(value) {
+			return json.Marshal(map[string]string{"text": string(value)})
+		}
+		return json.Marshal(map[string][]byte{"bytes": value})
+	case time.Time:
+		return json.Marshal(map[string]string{"time": value.Format(time.RFC3339Nano)})
+	}
+	return nil, fmt.Errorf("jormtest: cannot record a value of type %T", v.value)
+}
+
+// UnmarshalJSON decodes a value encoded by MarshalJSON
+func (v *recordedValue) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		v.value = nil
+		return nil
+	}
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(b, &typed); err != nil {
+		return err
+	}
+	if len(typed) != 1 {
+		return fmt.Errorf("jormtest: value %s is not keyed by a single type", b)
+	}
+
+	for typ, raw := range typed {
+		switch typ {
+		case "int64":
+			var value int64
+			return v.decode(raw, &value)
+		case "float64":
+			var value float64
+			return v.decode(raw, &value)
+		case "bool":
+			var value bool
+			return v.decode(raw, &value)
+		case "string":
+			var value string
+			return v.decode(raw, &value)
+		case "text":
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			v.value = []byte(value)
+			return nil
+		case "bytes":
+			var value []byte
+			return v.decode(raw, &value)
+		case "time":
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			t, err := time.Parse(time.RFC3339Nano, value)
+			v.value = t
+			return err
+		}
+		return fmt.Errorf("jormtest: unknown value type %q", typ)
+	}
+	return nil
+}
+
+// decode decodes raw into the pointer and sets the value to what it points to
+func (v *recordedValue) decode(raw json.RawMessage, ptr interface{}) error {
+	if err := json.Unmarshal(raw, ptr); err != nil {
+		return err
+	}
+	switch p := ptr.(type) {
+	case *int64:
+		v.value = *p
+	case *float64:
+		v.value = *p
+	case *bool:
+		v.value = *p
+	case *string:
+		v.value = *p
+	case *[]byte:
+		v.value = *p
+	}
+	return nil
+}
+
+// recordedValues returns the values of the args of a statement
+func recordedValues(args []driver.NamedValue) []recordedValue {
+	values := make([]recordedValue, len(args))
+	for i, arg := range args {
+		values[i] = recordedValue{value: arg.Value}
+	}
+	return values
+}
+
+// recorder is the driver.Connector of the connection pool of Record, its connections run statements on the pool
+// of the recorded db
+type recorder struct {
+	conn    *sql.DB
+	dialect string
+
+	mu         sync.Mutex
+	statements []recordedStatement
+}
+
+// Connect returns a new recording connection
+func (r *recorder) Connect(context.Context) (driver.Conn, error) {
+	return &recordConn{r: r}, nil
+}
+
+// Driver returns the recorder, which is its own driver
+func (r *recorder) Driver() driver.Driver {
+	return r
+}
+
+// Open returns a new recording connection whatever the name
+func (r *recorder) Open(string) (driver.Conn, error) {
+	return &recordConn{r: r}, nil
+}
+
+// add records a statement
+func (r *recorder) add(st recordedStatement) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, st)
+}
+
+// recordConn is a connection of Record, inside of a transaction its statements run in a transaction of the
+// recorded db
+type recordConn struct {
+	r  *recorder
+	tx *sql.Tx
+}
+
+// conn returns the transaction of the connection, or the recorded db outside of a transaction
+func (c *recordConn) conn() interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+} {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.r.conn
+}
+
+// Prepare returns a statement that is recorded when it runs
+func (c *recordConn) Prepare(query string) (driver.Stmt, error) {
+	return &connStmt{conn: c, query: query}, nil
+}
+
+// Close rolls back a transaction that was left open
+func (c *recordConn) Close() error {
+	if c.tx != nil {
+		return c.tx.Rollback()
+	}
+	return nil
+}
+
+// Begin begins a transaction
+func (c *recordConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx begins a transaction of the recorded db and records it as BEGIN
+func (c *recordConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.r.conn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.IsolationLevel(opts.Isolation), ReadOnly: opts.ReadOnly})
+	c.r.add(recordedStatement{SQL: "BEGIN", Error: newRecordedError(c.r.dialect, err)})
+	if err != nil {
+		return nil, err
+	}
+	c.tx = tx
+	return &recordTx{conn: c}, nil
+}
+
+// ExecContext runs a statement on the recorded db and records it with its rows affected and last insert ID
+func (c *recordConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	st := recordedStatement{SQL: query, Args: recordedValues(args)}
+	result, err := c.conn().ExecContext(ctx, query, namedArgs(args)...)
+	if err != nil {
+		st.Error = newRecordedError(c.r.dialect, err)
+		c.r.add(st)
+		return nil, err
+	}
+
+	st.RowsAffected, _ = result.RowsAffected()
+	st.LastInsertID, _ = result.LastInsertId()
+	c.r.add(st)
+	return result, nil
+}
+
+// QueryContext runs a query on the recorded db and records it with every row of its result set
+func (c *recordConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	st := recordedStatement{SQL: query, Query: true, Args: recordedValues(args)}
+	rows, err := c.conn().QueryContext(ctx, query, namedArgs(args)...)
+	if err == nil {
+		st.Columns, st.Rows, err = readRows(rows)
+	}
+	st.Error = newRecordedError(c.r.dialect, err)
+	c.r.add(st)
+	if err != nil {
+		return nil, err
+	}
+	return newMemRows(st), nil
+}
+
+// readRows reads and closes the rows
+func readRows(rows *sql.Rows) ([]string, [][]recordedValue, error) {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	var values [][]recordedValue
+	for rows.Next() {
+		row := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, err
+		}
+
+		recorded := make([]recordedValue, len(row))
+		for i, value := range row {
+			recorded[i] = recordedValue{value: value}
+		}
+		values = append(values, recorded)
+	}
+	return columns, values, rows.Err()
+}
+
+// namedArgs converts the args a driver receives back to the args of a statement
+func namedArgs(args []driver.NamedValue) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			values[i] = sql.Named(arg.Name, arg.Value)
+		} else {
+			values[i] = arg.Value
+		}
+	}
+	return values
+}
+
+// recordTx is a transaction of the recorded db that records its end
+type recordTx struct {
+	conn *recordConn
+}
+
+// Commit commits the transaction and records it as COMMIT
+func (tx *recordTx) Commit() error {
+	err := tx.conn.tx.Commit()
+	tx.conn.tx = nil
+	tx.conn.r.add(recordedStatement{SQL: "COMMIT", Error: newRecordedError(tx.conn.r.dialect, err)})
+	return err
+}
+
+// Rollback rolls back the transaction and records it as ROLLBACK
+func (tx *recordTx) Rollback() error {
+	err := tx.conn.tx.Rollback()
+	tx.conn.tx = nil
+	tx.conn.r.add(recordedStatement{SQL: "ROLLBACK", Error: newRecordedError(tx.conn.r.dialect, err)})
+	return err
+}
+
+// replayer is the driver.Connector of the connection pool of Replay
+type replayer struct {
+	t    testing.TB
+	path string
+
+	mu         sync.Mutex
+	statements []recordedStatement
+	pos        int
+	failed     bool
+}
+
+// Connect returns a new replaying connection
+func (r *replayer) Connect(context.Context) (driver.Conn, error) {
+	return &replayConn{r: r}, nil
+}
+
+// Driver returns the replayer, which is its own driver
+func (r *replayer) Driver() driver.Driver {
+	return r
+}
+
+// Open returns a new replaying connection whatever the name
+func (r *replayer) Open(string) (driver.Conn, error) {
+	return &replayConn{r: r}, nil
+}
+
+// next returns the next recorded statement, or fails the test if it differs from the statement run
+func (r *replayer) next(query bool, sql string, args []driver.NamedValue) (recordedStatement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failed {
+		return recordedStatement{}, errReplayMismatch
+	}
+
+	normalized := make([]string, len(args))
+	for i, arg := range args {
+		normalized[i] = NormalizeArg(arg.Value)
+	}
+	actual := statementLines(sql, normalized)
+	if !query {
+		actual[0] = "exec " + actual[0]
+	}
+	if r.pos == len(r.statements) {
+		r.failed = true
+		r.t.Errorf("jormtest: statement %d was not recorded in %s\n%s", r.pos+1, r.path, diffLines(nil, actual))
+		return recordedStatement{}, errReplayMismatch
+	}
+
+	st := r.statements[r.pos]
+	expected := statementLines(st.SQL, st.normalizedArgs())
+	if !st.Query {
+		expected[0] = "exec " + expected[0]
+	}
+	if strings.Join(expected, "\n") != strings.Join(actual, "\n") {
+		r.failed = true
+		r.t.Errorf("jormtest: statement %d differs from the one recorded in %s\n%s", r.pos+1, r.path,
+			diffLines(expected, actual))
+		return recordedStatement{}, errReplayMismatch
+	}
+
+	r.pos++
+	if st.Error != nil {
+		return st, st.Error.err()
+	}
+	return st, nil
+}
+
+// statementLines returns the normalized SQL and args of a statement as they are shown in a diff
+func statementLines(sql string, args []string) []string {
+	lines := []string{NormalizeSQL(sql)}
+	if len(args) > 0 {
+		lines = append(lines, "args: "+strings.Join(args, ", "))
+	}
+	return lines
+}
+
+// diffLines shows the expected lines prefixed with - and the actual lines prefixed with +
+func diffLines(expected, actual []string) string {
+	var b strings.Builder
+	for _, line := range expected {
+		b.WriteString("- " + line + "\n")
+	}
+	for _, line := range actual {
+		b.WriteString("+ " + line + "\n")
+	}
+	return b.String()
+}
+
+// replayConn is a connection of Replay
+type replayConn struct {
+	r *replayer
+}
+
+// Prepare returns a statement that is replayed when it runs
+func (c *replayConn) Prepare(query string) (driver.Stmt, error) {
+	return &connStmt{conn: c, query: query}, nil
+}
+
+// Close does nothing
+func (c *replayConn) Close() error {
+	return nil
+}
+
+// Begin replays the beginning of a transaction
+func (c *replayConn) Begin() (driver.Tx, error) {
+	if _, err := c.r.next(false, "BEGIN", nil); err != nil {
+		return nil, err
+	}
+	return &replayTx{r: c.r}, nil
+}
+
+// ExecContext replays a statement
+func (c *replayConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	st, err := c.r.next(false, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return replayResult{st: st}, nil
+}
+
+// QueryContext replays a query
+func (c *replayConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	st, err := c.r.next(true, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return newMemRows(st), nil
+}
+
+// replayTx replays the end of a transaction
+type replayTx struct {
+	r *replayer
+}
+
+// Commit replays a COMMIT
+func (tx *replayTx) Commit() error {
+	_, err := tx.r.next(false, "COMMIT", nil)
+	return err
+}
+
+// Rollback replays a ROLLBACK
+func (tx *replayTx) Rollback() error {
+	_, err := tx.r.next(false, "ROLLBACK", nil)
+	return err
+}
+
+// replayResult is the recorded result of a statement
+type replayResult struct {
+	st recordedStatement
+}
+
+// LastInsertId returns the recorded last insert ID
+func (r replayResult) LastInsertId() (int64, error) {
+	return r.st.LastInsertID, nil
+}
+
+// RowsAffected returns the recorded rows affected
+func (r replayResult) RowsAffected() (int64, error) {
+	return r.st.RowsAffected, nil
+}
+
+// connStmt is a prepared statement of a recording or replaying connection, which runs it on the connection
+type connStmt struct {
+	conn interface {
+		driver.ExecerContext
+		driver.QueryerContext
+	}
+	query string
+}
+
+// Close does nothing
+func (s *connStmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1 so the number of arguments is not checked
+func (s *connStmt) NumInput() int {
+	return -1
+}
+
+// Exec runs the statement on the connection
+func (s *connStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+// Query runs the query on the connection
+func (s *connStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+// namedValues converts the args of a prepared statement to the args of a connection
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// memRows are the recorded rows of a query
+type memRows struct {
+	columns []string
+	rows    [][]recordedValue
+	pos     int
+}
+
+// newMemRows returns the rows of a recorded query
+func newMemRows(st recordedStatement) *memRows {
+	return &memRows{columns: st.Columns, rows: st.Rows}
+}
+
+// Columns returns the recorded columns
+func (r *memRows) Columns() []string {
+	return r.columns
+}
+
+// Close does nothing
+func (r *memRows) Close() error {
+	return nil
+}
+
+// Next copies the next recorded row to dest
+func (r *memRows) Next(dest []driver.Value) error {
+	if r.pos == len(r.rows) {
+		return io.EOF
+	}
+	for i, value := range r.rows[r.pos] {
+		dest[i] = value.value
+	}
+	r.pos++
+	return nil
+}
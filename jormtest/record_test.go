@@ -0,0 +1,112 @@
+package jormtest_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormtest"
+)
+
+// signup is the outcome of the statements of the record and replay tests
+type signup struct {
+	user      user
+	duplicate error
+	blob      []byte
+}
+
+// signUp creates bob in a transaction, fails to create him again and then looks him up by name
+func signUp(db jorm.Interface, name string) signup {
+	var s signup
+	tx := db.Begin()
+	tx.Create(&user{ID: 1, Name: "bob", Age: 20})
+	s.duplicate = tx.Create(&user{ID: 1, Name: "bob"}).Error()
+	tx.Commit()
+
+	db.Where("name = ?", name).First(&s.user)
+	db.Raw("SELECT x'ff00' AS blob").Row().Scan(&s.blob)
+	return s
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recordings", "signup.json")
+
+	var recorded signup
+	t.Run("record", func(t *testing.T) {
+		recorded = signUp(jormtest.Record(t, openSQLite(t), path), "bob")
+	})
+	if recorded.user.Name != "bob" || recorded.duplicate == nil || string(recorded.blob) != "\xff\x00" {
+		t.Fatalf("recorded %+v", recorded)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec struct {
+		Dialect    string
+		Statements []struct{ SQL string }
+	}
+	if err := json.Unmarshal(b, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Dialect != "sqlite3" || len(rec.Statements) != 6 || rec.Statements[0].SQL != "BEGIN" || rec.Statements[3].SQL != "COMMIT" {
+		t.Errorf("recording = %s", b)
+	}
+
+	// the replay runs without a database
+	replayed := signUp(jormtest.Replay(t, path), "bob")
+	if replayed.user.ID != 1 || replayed.user.Name != "bob" || replayed.user.Age != 20 ||
+		!replayed.user.CreatedAt.Equal(recorded.user.CreatedAt) {
+		t.Errorf("replayed %+v, recorded %+v", replayed.user, recorded.user)
+	}
+	if replayed.duplicate == nil || replayed.duplicate.Error() != recorded.duplicate.Error() {
+		t.Errorf("replayed error %v, recorded %v", replayed.duplicate, recorded.duplicate)
+	}
+	if string(replayed.blob) != string(recorded.blob) {
+		t.Errorf("replayed blob %q", replayed.blob)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signup.json")
+	t.Run("record", func(t *testing.T) {
+		signUp(jormtest.Record(t, openSQLite(t), path), "bob")
+	})
+
+	tb := &recordingTB{}
+	t.Run("replay", func(t *testing.T) {
+		tb.TB = t
+		s := signUp(jormtest.Replay(tb, path), "alice")
+		if s.user.ID != 0 || s.blob != nil {
+			t.Errorf("replayed past the mismatch %+v", s)
+		}
+	})
+	if len(tb.errors) != 1 {
+		t.Fatalf("errors = %q", tb.errors)
+	}
+	if !strings.Contains(tb.errors[0], "statement 5 differs") ||
+		!strings.Contains(tb.errors[0], `- args: "bob"`) || !strings.Contains(tb.errors[0], `+ args: "alice"`) {
+		t.Errorf("error = %s", tb.errors[0])
+	}
+}
+
+func TestReplayUnreplayedStatements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signup.json")
+	t.Run("record", func(t *testing.T) {
+		signUp(jormtest.Record(t, openSQLite(t), path), "bob")
+	})
+
+	tb := &recordingTB{}
+	t.Run("replay", func(t *testing.T) {
+		tb.TB = t
+		db := jormtest.Replay(tb, path)
+		db.Begin().Create(&user{ID: 1, Name: "bob", Age: 20})
+	})
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "4 of the statements recorded") {
+		t.Errorf("errors = %q", tb.errors)
+	}
+}
@@ -0,0 +1,119 @@
+package jormtest
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jloom6/jorm"
+)
+
+// Rows is an in-memory jorm.Rows for tests, Scan converts values into its destinations the way database/sql does so
+// that it can be used with ScanRows and jorm.Each
+type Rows struct {
+	columns []string
+	values  [][]interface{}
+	row     int
+	err     error
+	closed  bool
+}
+
+// NewRows returns Rows with the given columns and one row per values, each row must have a value per column
+//     rows := jormtest.NewRows([]string{"id", "name"}, []interface{}{1, "bob"}, []interface{}{2, nil})
+func NewRows(columns []string, values ...[]interface{}) *Rows {
+	for i, v := range values {
+		if len(v) != len(columns) {
+			panic(fmt.Sprintf("jormtest: row %d has %d values for %d columns", i, len(v), len(columns)))
+		}
+	}
+	return &Rows{columns: columns, values: values, row: -1}
+}
+
+// WithError makes Err return err once every row has been read, as if reading the next row failed
+func (r *Rows) WithError(err error) *Rows {
+	r.err = err
+	return r
+}
+
+// Closed reports whether Close was called
+func (r *Rows) Closed() bool {
+	return r.closed
+}
+
+// Next prepares the next row for Scan, it returns false after the last row or once the rows are closed
+func (r *Rows) Next() bool {
+	if r.closed || r.row >= len(r.values) {
+		return false
+	}
+	r.row++
+	return r.row < len(r.values)
+}
+
+// NextResultSet returns false as Rows only has a single result set
+func (r *Rows) NextResultSet() bool {
+	return false
+}
+
+// Err returns the error given to WithError once every row has been read
+func (r *Rows) Err() error {
+	if r.row >= len(r.values) {
+		return r.err
+	}
+	return nil
+}
+
+// Columns returns the column names
+func (r *Rows) Columns() ([]string, error) {
+	if r.closed {
+		return nil, errors.New("jormtest: Rows are closed")
+	}
+	return r.columns, nil
+}
+
+// ColumnTypes returns an error as a *sql.ColumnType cannot be created outside of database/sql
+func (r *Rows) ColumnTypes() ([]*sql.ColumnType, error) {
+	return nil, errors.New("jormtest: Rows does not support ColumnTypes")
+}
+
+// Scan copies the values of the current row into dest, converting them the way database/sql would
+func (r *Rows) Scan(dest ...interface{}) error {
+	switch {
+	case r.closed:
+		return errors.New("jormtest: Rows are closed")
+	case r.row < 0 || r.row >= len(r.values):
+		return errors.New("jormtest: Scan called without calling Next")
+	case len(dest) != len(r.columns):
+		return fmt.Errorf("jormtest: expected %d destination arguments in Scan, not %d", len(r.columns), len(dest))
+	}
+
+	for i, d := range dest {
+		rv := reflect.ValueOf(d)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return fmt.Errorf("jormtest: destination %d is not a non nil pointer: %T", i, d)
+		}
+		if err := assign(rv.Elem(), r.values[r.row][i]); err != nil {
+			return fmt.Errorf("jormtest: scanning column %q: %v", r.columns[i], err)
+		}
+	}
+	return nil
+}
+
+// Close closes the rows, Next returns false afterwards
+func (r *Rows) Close() error {
+	r.closed = true
+	return nil
+}
+
+// errRow is a jorm.Row whose Scan returns the error of its query
+type errRow struct {
+	err error
+}
+
+// Scan returns the error of the query
+func (r errRow) Scan(dest ...interface{}) error {
+	return r.err
+}
+
+// compile time check that Rows implements the interface
+var _ jorm.Rows = (*Rows)(nil)
@@ -0,0 +1,48 @@
+package jormtest
+
+import (
+	"reflect"
+	"sync"
+)
+
+// store holds the records of every model type of a Fake
+type store struct {
+	mu     sync.Mutex
+	tables map[reflect.Type][]reflect.Value
+}
+
+// newStore returns an empty store
+func newStore() *store {
+	return &store{tables: map[reflect.Type][]reflect.Value{}}
+}
+
+// snapshot returns a copy of every table so that it can be restored later
+func (s *store) snapshot() map[reflect.Type][]reflect.Value {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tables := make(map[reflect.Type][]reflect.Value, len(s.tables))
+	for typ, records := range s.tables {
+		copies := make([]reflect.Value, len(records))
+		for i, record := range records {
+			copies[i] = copyRecord(record)
+		}
+		tables[typ] = copies
+	}
+	return tables
+}
+
+// restore replaces every table with the given snapshot
+func (s *store) restore(tables map[reflect.Type][]reflect.Value) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tables = tables
+}
+
+// copyRecord returns an addressable copy of the record
+func copyRecord(record reflect.Value) reflect.Value {
+	c := reflect.New(record.Type()).Elem()
+	c.Set(record)
+	return c
+}
@@ -0,0 +1,37 @@
+// Package jormzap adapts a zap logger to jorm.Logger
+//     db = db.WithLogger(jormzap.New(zapLogger))
+package jormzap
+
+import (
+	"context"
+
+	"github.com/jloom6/jorm"
+	"go.uber.org/zap"
+)
+
+// Logger is a jorm.Logger that logs with a *zap.SugaredLogger
+type Logger struct {
+	logger *zap.SugaredLogger
+}
+
+// New returns a Logger that logs with the given *zap.Logger
+func New(logger *zap.Logger) *Logger {
+	return &Logger{logger: logger.WithOptions(zap.AddCallerSkip(1)).Sugar()}
+}
+
+// Log logs the message at the zap level matching the level with the key/value pairs as fields
+func (l *Logger) Log(ctx context.Context, level jorm.Level, msg string, keyvals ...interface{}) {
+	switch level {
+	case jorm.LevelDebug:
+		l.logger.Debugw(msg, keyvals...)
+	case jorm.LevelWarn:
+		l.logger.Warnw(msg, keyvals...)
+	case jorm.LevelError:
+		l.logger.Errorw(msg, keyvals...)
+	default:
+		l.logger.Infow(msg, keyvals...)
+	}
+}
+
+// compile time check that Logger implements the interface
+var _ jorm.Logger = (*Logger)(nil)
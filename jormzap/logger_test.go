@@ -0,0 +1,35 @@
+package jormzap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLog(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := jormzap.New(zap.New(core))
+
+	levels := map[jorm.Level]zapcore.Level{
+		jorm.LevelDebug: zap.DebugLevel,
+		jorm.LevelInfo:  zap.InfoLevel,
+		jorm.LevelWarn:  zap.WarnLevel,
+		jorm.LevelError: zap.ErrorLevel,
+	}
+	for level, want := range levels {
+		logger.Log(context.Background(), level, "statement", "rows", 3)
+
+		entries := logs.TakeAll()
+		if len(entries) != 1 {
+			t.Fatalf("logged %+v", entries)
+		}
+		if entries[0].Level != want || entries[0].Message != "statement" || entries[0].ContextMap()["rows"] != int64(3) {
+			t.Errorf("Log at %v = %+v", level, entries[0])
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package jorm
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jinzhu/gorm"
+)
+
+// gorm settings the row lock of the queries of a db and the connection pool its transaction was begun on are
+// stored under
+const (
+	lockSetting = "jorm:lock"
+	poolSetting = "jorm:pool"
+)
+
+var (
+	// ErrNotInTransaction is returned by locking reads, made with ForUpdate, ForShare, SkipLocked or NoWait, outside
+	// of a transaction where their locks would be released as soon as they return
+	ErrNotInTransaction = errors.New("jorm: locking read outside of a transaction")
+	// ErrLockNotSupported is returned by locking reads on a dialect without row locks, and by SkipLocked and NoWait
+	// before MySQL 8.0
+	ErrLockNotSupported = errors.New("jorm: locking reads are not supported by the dialect")
+)
+
+// serverVersions caches the SELECT VERSION() of the MySQL servers of connection pools
+var serverVersions sync.Map
+
+// rowLock is the row lock of the queries of a db
+type rowLock struct {
+	share bool
+	// wait is how the query waits for rows locked by other transactions, empty to wait until they are released
+	wait string
+}
+
+// clause returns the locking clause of the row lock in the dialect and server version, empty if the dialect locks the
+// whole database in transactions, or ErrLockNotSupported. MySQL before 8.0 and MariaDB only take shared locks with
+// LOCK IN SHARE MODE, and MySQL before 8.0 cannot skip locked rows or not wait for them
+func (l rowLock) clause(dialect, version string) (string, error) {
+	share := "FOR SHARE"
+	switch dialect {
+	case "mysql":
+		if legacyMySQL(version) {
+			if l.wait != "" {
+				return "", ErrLockNotSupported
+			}
+			share = "LOCK IN SHARE MODE"
+		} else if strings.Contains(strings.ToLower(version), "mariadb") {
+			share = "LOCK IN SHARE MODE"
+		}
+	case "postgres":
+	case "sqlite3":
+		return "", nil
+	default:
+		return "", ErrLockNotSupported
+	}
+
+	clause := "FOR UPDATE"
+	if l.share {
+		clause = share
+	}
+	if l.wait != "" {
+		clause += " " + l.wait
+	}
+	return clause, nil
+}
+
+// legacyMySQL reports whether the version is of a MySQL server before 8.0, MariaDB versions start at 10
+func legacyMySQL(version string) bool {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	return err == nil && major < 8
+}
+
+// ForUpdate returns a clone of the db whose queries lock the rows they read until the transaction ends, as if they
+// were updated. It must be used inside a transaction, queries fail with ErrNotInTransaction otherwise
+//     tx.ForUpdate().First(&account, id) // SELECT * FROM accounts WHERE id = ? FOR UPDATE
+func (db *DB) ForUpdate() Interface {
+	return db.withLock(func(lock *rowLock) {
+		lock.share = false
+	})
+}
+
+// ForShare returns a clone of the db whose queries take a shared lock on the rows they read until the transaction
+// ends, so other transactions can read but not update them. It must be used inside a transaction
+func (db *DB) ForShare() Interface {
+	return db.withLock(func(lock *rowLock) {
+		lock.share = true
+	})
+}
+
+// SkipLocked returns a clone of the db whose locking queries skip the rows other transactions have locked rather
+// than wait for them, FOR UPDATE unless ForShare is used. It must be used inside a transaction
+//     tx.SkipLocked().Where("status = ?", "pending").Limit(10).Find(&jobs) // ... FOR UPDATE SKIP LOCKED
+func (db *DB) SkipLocked() Interface {
+	return db.withLock(func(lock *rowLock) {
+		lock.wait = "SKIP LOCKED"
+	})
+}
+
+// NoWait returns a clone of the db whose locking queries fail with ErrLockTimeout rather than wait for rows other
+// transactions have locked, FOR UPDATE unless ForShare is used. It must be used inside a transaction
+func (db *DB) NoWait() Interface {
+	return db.withLock(func(lock *rowLock) {
+		lock.wait = "NOWAIT"
+	})
+}
+
+// withLock returns a clone of the db whose row lock is changed by fn
+func (db *DB) withLock(fn func(lock *rowLock)) Interface {
+	value, _ := db.db.Get(lockSetting)
+	lock, _ := value.(rowLock)
+	fn(&lock)
+	return db.clone(db.db.Set(lockSetting, lock))
+}
+
+var registerLockCallbacksMu sync.Mutex
+
+// registerLockCallbacks registers the gorm callbacks that add the locking clause of locking reads,
+// gorm shares callbacks between every db opened together so they are registered once
+func registerLockCallbacks(db *gorm.DB) {
+	registerLockCallbacksMu.Lock()
+	defer registerLockCallbacksMu.Unlock()
+
+	callback := db.Callback()
+	if callback.Query().Get("jorm:lock") != nil {
+		return
+	}
+
+	callback.Query().Before("gorm:query").Register("jorm:lock", func(scope *gorm.Scope) {
+		scope.Err(lockRows(scope))
+	})
+	callback.RowQuery().Before("gorm:row_query").Register("jorm:lock", func(scope *gorm.Scope) {
+		if err := lockRows(scope); err != nil {
+			refuseRowQuery(scope, err)
+		}
+	})
+}
+
+// lockRows sets the locking clause of a locking read as its query option
+func lockRows(scope *gorm.Scope) error {
+	value, ok := scope.Get(lockSetting)
+	if !ok || scope.HasError() {
+		return nil
+	}
+	if _, inTx := unwrapConn(scope.SQLDB()).(sqlTx); !inTx {
+		if dryRun, _ := scope.Get(dryRunSetting); dryRun != true {
+			return ErrNotInTransaction
+		}
+	}
+
+	dialect := scope.Dialect().GetName()
+	var version string
+	if dialect == "mysql" {
+		var err error
+		if version, err = serverVersion(scope); err != nil {
+			return err
+		}
+	}
+	clause, err := value.(rowLock).clause(dialect, version)
+	if err != nil || clause == "" {
+		return err
+	}
+	// keep the caller's query option, the scopes of preloads inherit the one already locked
+	if option, ok := scope.Get("gorm:query_option"); ok {
+		option, _ := option.(string)
+		if strings.HasSuffix(option, clause) {
+			return nil
+		}
+		if option != "" {
+			clause = option + " " + clause
+		}
+	}
+	scope.Set("gorm:query_option", clause)
+	return nil
+}
+
+// serverVersion returns the version of the MySQL server of the scope, cached per connection pool. Dry runs have no
+// server and use the syntax of the latest version
+func serverVersion(scope *gorm.Scope) (string, error) {
+	if dryRun, _ := scope.Get(dryRunSetting); dryRun == true {
+		return "", nil
+	}
+
+	pool, _ := unwrapConn(scope.SQLDB()).(*sql.DB)
+	if value, ok := scope.Get(poolSetting); ok {
+		pool, _ = value.(*sql.DB)
+	}
+	if pool != nil {
+		if version, ok := serverVersions.Load(pool); ok {
+			return version.(string), nil
+		}
+	}
+
+	var version string
+	if err := scope.SQLDB().QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return "", err
+	}
+	if pool != nil {
+		serverVersions.Store(pool, version)
+	}
+	return version, nil
+}
@@ -0,0 +1,89 @@
+package jorm
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+type lockJob struct {
+	ID     uint
+	Status string
+}
+
+func TestRowLockClause(t *testing.T) {
+	tests := []struct {
+		lock    rowLock
+		dialect string
+		version string
+		clause  string
+		err     error
+	}{
+		{rowLock{}, "mysql", "8.0.33", "FOR UPDATE", nil},
+		{rowLock{share: true, wait: "NOWAIT"}, "mysql", "8.0.33", "FOR SHARE NOWAIT", nil},
+		{rowLock{wait: "SKIP LOCKED"}, "mysql", "", "FOR UPDATE SKIP LOCKED", nil},
+		{rowLock{}, "mysql", "5.7.42-log", "FOR UPDATE", nil},
+		{rowLock{share: true}, "mysql", "5.7.42-log", "LOCK IN SHARE MODE", nil},
+		{rowLock{wait: "SKIP LOCKED"}, "mysql", "5.7.42-log", "", ErrLockNotSupported},
+		{rowLock{share: true, wait: "NOWAIT"}, "mysql", "5.6.51", "", ErrLockNotSupported},
+		{rowLock{share: true, wait: "SKIP LOCKED"}, "mysql", "10.6.12-MariaDB", "LOCK IN SHARE MODE SKIP LOCKED", nil},
+		{rowLock{share: true, wait: "SKIP LOCKED"}, "postgres", "", "FOR SHARE SKIP LOCKED", nil},
+		{rowLock{share: true}, "sqlite3", "", "", nil},
+		{rowLock{}, "mssql", "", "", ErrLockNotSupported},
+	}
+	for _, test := range tests {
+		clause, err := test.lock.clause(test.dialect, test.version)
+		if clause != test.clause || err != test.err {
+			t.Errorf("%+v.clause(%q, %q) = %q, %v, want %q, %v", test.lock, test.dialect, test.version, clause, err, test.clause, test.err)
+		}
+	}
+}
+
+func TestLockKeepsQueryOption(t *testing.T) {
+	g, err := gorm.Open("mysql", dryRunDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := NewDB(g)
+
+	sql, _, err := db.ToSQL(func(tx Interface) Interface {
+		return tx.Set("gorm:query_option", "/* report */").SkipLocked().Where("status = ?", "pending").Find(&[]lockJob{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT * FROM `lock_jobs`  WHERE (status = ?) /* report */ FOR UPDATE SKIP LOCKED"
+	if sql != want {
+		t.Errorf("ToSQL = %q, want %q", sql, want)
+	}
+}
+
+func TestLockOutsideTransaction(t *testing.T) {
+	g, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	db := NewDB(g)
+	if err := db.AutoMigrate(&lockJob{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.ForUpdate().Find(&[]lockJob{}).Error(); !errors.Is(err, ErrNotInTransaction) {
+		t.Errorf("Find outside of a transaction = %v", err)
+	}
+	var n int
+	if err := db.ForUpdate().Model(&lockJob{}).Select("count(*)").Row().Scan(&n); !errors.Is(err, ErrNotInTransaction) {
+		t.Errorf("Row outside of a transaction = %v", err)
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+	if err := tx.ForUpdate().SkipLocked().Find(&[]lockJob{}).Error(); err != nil {
+		t.Errorf("Find in a transaction = %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package jorm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// gorm settings and statement hook name of the logger set with WithLogger
+const (
+	loggerSetting  = "jorm:logger"
+	logModeSetting = "jorm:log_mode"
+	loggerHookName = "jorm:logger"
+)
+
+// Level is the severity of a log entry
+type Level int
+
+// Levels of log entries, from the least to the most severe
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the lower cased name of the level
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// Logger is a leveled logger with key/value fields, keyvals alternate between a string key and its value.
+// Statements are logged at LevelDebug with the statement, args, duration, rows, error, trace_id and caller fields
+type Logger interface {
+	Log(ctx context.Context, level Level, msg string, keyvals ...interface{})
+}
+
+// WithLogger returns a clone of the db that logs with the given logger, the statements of LogMode(true) and Debug
+// and gorm's own messages included
+//     db = db.WithLogger(jorm.NewSlogLogger(slog.Default()))
+func (db *DB) WithLogger(logger Logger) Interface {
+	c := db.OnStatement(loggerHookName, logStatement)
+	c.db = c.db.Set(loggerSetting, logger)
+	c.db.SetLogger(gormLogger{logger: logger})
+	return c
+}
+
+// loggerOf returns the logger set to the db with WithLogger, nil if there is none
+func loggerOf(get func(name string) (interface{}, bool)) Logger {
+	if logger, ok := get(loggerSetting); ok {
+		return logger.(Logger)
+	}
+	return nil
+}
+
+// logStatement logs a statement if the log mode of its db is on
+func logStatement(stmt *Statement) {
+	if enabled, _ := stmt.Get(logModeSetting); enabled != true {
+		return
+	}
+	logger := loggerOf(stmt.Get)
+	if logger == nil {
+		return
+	}
+
+	keyvals := []interface{}{
+		"statement", strings.TrimSpace(stmt.SQL),
+		"args", stmt.Vars,
+		"duration", stmt.Duration,
+		"rows", stmt.RowsAffected,
+		"caller", caller(),
+	}
+	if stmt.Error != nil {
+		keyvals = append(keyvals, "error", stmt.Error)
+	}
+	if traceID := stmt.TraceID(); traceID != "" {
+		keyvals = append(keyvals, "trace_id", traceID)
+	}
+	logger.Log(stmt.Context, LevelDebug, "statement", keyvals...)
+}
+
+// gormLogger passes the messages gorm prints to a Logger, statements are left to logStatement which knows their context
+type gormLogger struct {
+	logger Logger
+}
+
+// Print logs a message printed by gorm, the first value is its kind and the second where it comes from
+func (l gormLogger) Print(v ...interface{}) {
+	if len(v) < 2 {
+		l.logger.Log(context.Background(), LevelInfo, fmt.Sprint(v...))
+		return
+	}
+
+	switch v[0] {
+	case "sql":
+	case "error":
+		l.logger.Log(context.Background(), LevelError, "error", "error", fmt.Sprint(v[2:]...), "caller", v[1])
+	case "info":
+		l.logger.Log(context.Background(), LevelDebug, fmt.Sprint(v[1:]...))
+	case "log":
+		// gorm logs the errors it adds in log mode, which are errors all the same
+		if err, ok := v[len(v)-1].(error); ok {
+			l.logger.Log(context.Background(), LevelError, "error", "error", err, "caller", v[1])
+			return
+		}
+		l.logger.Log(context.Background(), LevelInfo, fmt.Sprint(v[2:]...), "caller", v[1])
+	default:
+		l.logger.Log(context.Background(), LevelInfo, fmt.Sprint(v[2:]...), "caller", v[1])
+	}
+}
+
+// slogLogger adapts a *slog.Logger to the Logger interface
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger that logs with the given *slog.Logger
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger: logger}
+}
+
+// Log logs the message at the slog level matching the level
+func (l slogLogger) Log(ctx context.Context, level Level, msg string, keyvals ...interface{}) {
+	slogLevel := slog.LevelInfo
+	switch level {
+	case LevelDebug:
+		slogLevel = slog.LevelDebug
+	case LevelWarn:
+		slogLevel = slog.LevelWarn
+	case LevelError:
+		slogLevel = slog.LevelError
+	}
+	l.logger.Log(ctx, slogLevel, msg, keyvals...)
+}
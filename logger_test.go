@@ -0,0 +1,100 @@
+package jorm_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jloom6/jorm"
+)
+
+type entry struct {
+	level   jorm.Level
+	msg     string
+	keyvals map[string]interface{}
+}
+
+// recordingLogger is a jorm.Logger that keeps the entries it logs
+type recordingLogger struct {
+	entries []entry
+}
+
+func (l *recordingLogger) Log(ctx context.Context, level jorm.Level, msg string, keyvals ...interface{}) {
+	e := entry{level: level, msg: msg, keyvals: map[string]interface{}{}}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		e.keyvals[keyvals[i].(string)] = keyvals[i+1]
+	}
+	l.entries = append(l.entries, e)
+}
+
+func TestLoggerLogsStatementsInLogMode(t *testing.T) {
+	logger := &recordingLogger{}
+	db := open(t).WithLogger(logger)
+
+	if err := db.Create(&user{Name: "quiet"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.entries) != 0 {
+		t.Fatalf("logged without log mode %+v", logger.entries)
+	}
+
+	if err := db.Debug().Where("name = ?", "loud").Find(&[]user{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.entries) != 1 {
+		t.Fatalf("logged %+v", logger.entries)
+	}
+	e := logger.entries[0]
+	if e.level != jorm.LevelDebug || e.msg != "statement" || !strings.HasPrefix(e.keyvals["statement"].(string), "SELECT") {
+		t.Errorf("entry = %+v", e)
+	}
+	if !strings.Contains(e.keyvals["caller"].(string), "logger_test.go:") {
+		t.Errorf("caller = %v", e.keyvals["caller"])
+	}
+	if _, ok := e.keyvals["error"]; ok {
+		t.Errorf("error = %v", e.keyvals["error"])
+	}
+}
+
+func TestLoggerLogsErrors(t *testing.T) {
+	logger := &recordingLogger{}
+	db := open(t).WithLogger(logger)
+
+	if err := db.Debug().Exec("INSERT INTO missing VALUES (1)").Error(); err == nil {
+		t.Fatal("Exec into a missing table has no error")
+	}
+	var statement, failure bool
+	for _, e := range logger.entries {
+		switch {
+		case e.msg == "statement" && e.keyvals["error"] != nil:
+			statement = true
+		case e.level == jorm.LevelError:
+			failure = true
+		}
+	}
+	if !statement || !failure {
+		t.Errorf("logged %+v", logger.entries)
+	}
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := jorm.NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	logger.Log(context.Background(), jorm.LevelWarn, "slow query", "rows", 3)
+	if out := buf.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, `msg="slow query" rows=3`) {
+		t.Errorf("logged %q", out)
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	for level, want := range map[jorm.Level]string{
+		jorm.LevelDebug: "debug", jorm.LevelInfo: "info", jorm.LevelWarn: "warn", jorm.LevelError: "error", 7: "level(7)",
+	} {
+		if got := level.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// ChainMock is maintained by hand alongside the generated MockInterface, the compile time check at the bottom of
+// this file fails when a method is added to jorm.QueryBuilder without being added to chain
+
+package mocks
+
+import (
+	context "context"
+	fmt "fmt"
+	gomock "github.com/golang/mock/gomock"
+	gorm "github.com/jinzhu/gorm"
+	jorm "github.com/jloom6/jorm"
+	sync "sync"
+)
+
+// ChainMock is a MockInterface whose builder methods, the methods of jorm.QueryBuilder and WithContext, New,
+// WithLogger, LogMode, Debug, UsePrimary, WithoutTenantScope, BlockGlobalUpdate, Set and InstantSet, return the ChainMock itself and record their arguments.
+// Only terminal methods such as Find, First, Create, Exec, Error and RowsAffected need expectations
+//     db := mocks.NewChainMock(ctrl)
+//     db.EXPECT().Find(gomock.Any()).Return(db)
+//     db.EXPECT().Error().Return(nil)
+//
+//     findUsers(db, "bob")
+//
+//     db.AssertCalled(t, "Where", "name = ?", "bob")
+type ChainMock struct {
+	chain
+	generated
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Call is a builder method call recorded by a ChainMock, variadic arguments are flattened into Args
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// chain holds the builder methods so they are promoted ahead of the ones of the generated mock
+type chain struct {
+	mock *ChainMock
+}
+
+// generated holds the generated mock one level deeper than chain so its builder methods are shadowed
+type generated struct {
+	*MockInterface
+}
+
+// NewChainMock creates a new chain tolerant mock instance
+func NewChainMock(ctrl *gomock.Controller) *ChainMock {
+	mock := &ChainMock{generated: generated{NewMockInterface(ctrl)}}
+	mock.chain.mock = mock
+	return mock
+}
+
+// Calls returns the recorded calls of the given builder method in order, or every recorded call if method is empty
+func (m *ChainMock) Calls(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, call := range m.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// AssertCalled fails the test unless the builder method was called with the given arguments,
+// an argument can be a gomock.Matcher such as gomock.Any()
+func (m *ChainMock) AssertCalled(t gomock.TestReporter, method string, args ...interface{}) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if !m.called(method, args) {
+		t.Errorf("expected call of %s(%s), recorded calls: %v", method, formatArgs(args), m.Calls(""))
+	}
+}
+
+// AssertNotCalled fails the test if the builder method was called with the given arguments
+func (m *ChainMock) AssertNotCalled(t gomock.TestReporter, method string, args ...interface{}) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if m.called(method, args) {
+		t.Errorf("unexpected call of %s(%s)", method, formatArgs(args))
+	}
+}
+
+// called reports whether a call of the method matching the arguments was recorded
+func (m *ChainMock) called(method string, args []interface{}) bool {
+	for _, call := range m.Calls(method) {
+		if matchArgs(args, call.Args) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchArgs reports whether the recorded arguments match the expected ones
+func matchArgs(expected, actual []interface{}) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i, e := range expected {
+		matcher, ok := e.(gomock.Matcher)
+		if !ok {
+			matcher = gomock.Eq(e)
+		}
+		if !matcher.Matches(actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatArgs formats arguments for a failure message
+func formatArgs(args []interface{}) string {
+	s := fmt.Sprint(args)
+	return s[1 : len(s)-1]
+}
+
+// record records a call of a builder method and returns the mock to continue the chain
+func (c chain) record(method string, args ...interface{}) jorm.Interface {
+	c.mock.mu.Lock()
+	defer c.mock.mu.Unlock()
+
+	c.mock.calls = append(c.mock.calls, Call{Method: method, Args: args})
+	return c.mock
+}
+
+// WithContext records the call and returns the mock
+func (c chain) WithContext(ctx context.Context) jorm.Interface {
+	return c.record("WithContext", ctx)
+}
+
+// New records the call and returns the mock
+func (c chain) New() jorm.Interface {
+	return c.record("New")
+}
+
+// WithLogger records the call and returns the mock
+func (c chain) WithLogger(logger jorm.Logger) jorm.Interface {
+	return c.record("WithLogger", logger)
+}
+
+// LogMode records the call and returns the mock
+func (c chain) LogMode(enable bool) jorm.Interface {
+	return c.record("LogMode", enable)
+}
+
+// BlockGlobalUpdate records the call and returns the mock
+func (c chain) BlockGlobalUpdate(enable bool) jorm.Interface {
+	return c.record("BlockGlobalUpdate", enable)
+}
+
+// Debug records the call and returns the mock
+func (c chain) Debug() jorm.Interface {
+	return c.record("Debug")
+}
+
+// UsePrimary records the call and returns the mock
+func (c chain) UsePrimary() jorm.Interface {
+	return c.record("UsePrimary")
+}
+
+// WithoutTenantScope records the call and returns the mock
+func (c chain) WithoutTenantScope() jorm.Interface {
+	return c.record("WithoutTenantScope")
+}
+
+// Set records the call and returns the mock
+func (c chain) Set(name string, value interface{}) jorm.Interface {
+	return c.record("Set", name, value)
+}
+
+// InstantSet records the call and returns the mock
+func (c chain) InstantSet(name string, value interface{}) jorm.Interface {
+	return c.record("InstantSet", name, value)
+}
+
+// Where records the call and returns the mock
+func (c chain) Where(query interface{}, args ...interface{}) jorm.Interface {
+	return c.record("Where", append([]interface{}{query}, args...)...)
+}
+
+// Or records the call and returns the mock
+func (c chain) Or(query interface{}, args ...interface{}) jorm.Interface {
+	return c.record("Or", append([]interface{}{query}, args...)...)
+}
+
+// Not records the call and returns the mock
+func (c chain) Not(query interface{}, args ...interface{}) jorm.Interface {
+	return c.record("Not", append([]interface{}{query}, args...)...)
+}
+
+// Limit records the call and returns the mock
+func (c chain) Limit(limit interface{}) jorm.Interface {
+	return c.record("Limit", limit)
+}
+
+// Offset records the call and returns the mock
+func (c chain) Offset(offset interface{}) jorm.Interface {
+	return c.record("Offset", offset)
+}
+
+// Order records the call and returns the mock
+func (c chain) Order(value interface{}, reorder ...bool) jorm.Interface {
+	args := []interface{}{value}
+	for _, r := range reorder {
+		args = append(args, r)
+	}
+	return c.record("Order", args...)
+}
+
+// Select records the call and returns the mock
+func (c chain) Select(query interface{}, args ...interface{}) jorm.Interface {
+	return c.record("Select", append([]interface{}{query}, args...)...)
+}
+
+// Omit records the call and returns the mock
+func (c chain) Omit(columns ...string) jorm.Interface {
+	args := []interface{}{}
+	for _, column := range columns {
+		args = append(args, column)
+	}
+	return c.record("Omit", args...)
+}
+
+// Group records the call and returns the mock
+func (c chain) Group(query string) jorm.Interface {
+	return c.record("Group", query)
+}
+
+// Having records the call and returns the mock
+func (c chain) Having(query interface{}, values ...interface{}) jorm.Interface {
+	return c.record("Having", append([]interface{}{query}, values...)...)
+}
+
+// Joins records the call and returns the mock
+func (c chain) Joins(query string, args ...interface{}) jorm.Interface {
+	return c.record("Joins", append([]interface{}{query}, args...)...)
+}
+
+// Scopes records the call and returns the mock, the scopes are not run
+func (c chain) Scopes(funcs ...func(*gorm.DB) *gorm.DB) jorm.Interface {
+	args := []interface{}{}
+	for _, f := range funcs {
+		args = append(args, f)
+	}
+	return c.record("Scopes", args...)
+}
+
+// Unscoped records the call and returns the mock
+func (c chain) Unscoped() jorm.Interface {
+	return c.record("Unscoped")
+}
+
+// Assign records the call and returns the mock
+func (c chain) Assign(attrs ...interface{}) jorm.Interface {
+	return c.record("Assign", attrs...)
+}
+
+// Attrs records the call and returns the mock
+func (c chain) Attrs(attrs ...interface{}) jorm.Interface {
+	return c.record("Attrs", attrs...)
+}
+
+// Raw records the call and returns the mock
+func (c chain) Raw(sql string, values ...interface{}) jorm.Interface {
+	return c.record("Raw", append([]interface{}{sql}, values...)...)
+}
+
+// Model records the call and returns the mock
+func (c chain) Model(value interface{}) jorm.Interface {
+	return c.record("Model", value)
+}
+
+// Table records the call and returns the mock
+func (c chain) Table(name string) jorm.Interface {
+	return c.record("Table", name)
+}
+
+// Preload records the call and returns the mock
+func (c chain) Preload(column string, conditions ...interface{}) jorm.Interface {
+	return c.record("Preload", append([]interface{}{column}, conditions...)...)
+}
+
+// ForUpdate records the call and returns the mock
+func (c chain) ForUpdate() jorm.Interface {
+	return c.record("ForUpdate")
+}
+
+// ForShare records the call and returns the mock
+func (c chain) ForShare() jorm.Interface {
+	return c.record("ForShare")
+}
+
+// SkipLocked records the call and returns the mock
+func (c chain) SkipLocked() jorm.Interface {
+	return c.record("SkipLocked")
+}
+
+// NoWait records the call and returns the mock
+func (c chain) NoWait() jorm.Interface {
+	return c.record("NoWait")
+}
+
+// compile time checks that chain covers every builder method and that ChainMock implements the interface
+var (
+	_ jorm.QueryBuilder = chain{}
+	_ jorm.Interface    = (*ChainMock)(nil)
+)
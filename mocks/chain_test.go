@@ -0,0 +1,88 @@
+package mocks_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/mocks"
+)
+
+type user struct {
+	ID   uint
+	Name string
+}
+
+// reporter records the failures reported to it instead of failing the test
+type reporter struct {
+	failures []string
+}
+
+func (r *reporter) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *reporter) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func findByName(ctx context.Context, db jorm.Interface, name string) ([]user, error) {
+	var users []user
+	err := db.WithContext(ctx).Where("name = ?", name).Order("id").Limit(10).Find(&users).Error()
+	return users, err
+}
+
+func TestChainMock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := mocks.NewChainMock(ctrl)
+	db.EXPECT().Find(gomock.Any()).DoAndReturn(func(out interface{}, where ...interface{}) jorm.Interface {
+		*out.(*[]user) = []user{{ID: 1, Name: "bob"}}
+		return db
+	})
+	db.EXPECT().Error().Return(nil)
+
+	users, err := findByName(context.Background(), db, "bob")
+	if err != nil || len(users) != 1 || users[0].Name != "bob" {
+		t.Fatalf("findByName = %v %v", users, err)
+	}
+
+	db.AssertCalled(t, "Where", "name = ?", "bob")
+	db.AssertCalled(t, "Limit", 10)
+	db.AssertCalled(t, "WithContext", gomock.Any())
+	db.AssertNotCalled(t, "Where", "name = ?", "alice")
+	db.AssertNotCalled(t, "Offset", gomock.Any())
+
+	calls := db.Calls("")
+	want := []string{"WithContext", "Where", "Order", "Limit"}
+	if len(calls) != len(want) {
+		t.Fatalf("Calls = %v", calls)
+	}
+	for i, call := range calls {
+		if call.Method != want[i] {
+			t.Errorf("call %d = %s, want %s", i, call.Method, want[i])
+		}
+	}
+	if where := db.Calls("Where"); len(where) != 1 || fmt.Sprint(where[0].Args) != "[name = ? bob]" {
+		t.Errorf("Calls(Where) = %v", where)
+	}
+}
+
+func TestChainMockAssertionFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := mocks.NewChainMock(ctrl)
+	db.Where("name = ?", "bob")
+
+	r := &reporter{}
+	db.AssertCalled(r, "Where", "name = ?", "alice")
+	db.AssertNotCalled(r, "Where", "name = ?", gomock.Any())
+	db.AssertCalled(r, "Order", "id")
+	if len(r.failures) != 3 {
+		t.Errorf("failures = %q", r.failures)
+	}
+}
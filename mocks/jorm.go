@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/jloom6/jorm (interfaces: Interface,Row,Rows)
+// Source: github.com/jloom6/jorm (interfaces: Interface,Row,Rows,SQLCommon,Association,Callback,CallbackProcessor,Dialect)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -112,9 +112,9 @@ func (mr *MockInterfaceMockRecorder) Assign(arg0 ...interface{}) *gomock.Call {
 }
 
 // Association mocks base method
-func (m *MockInterface) Association(arg0 string) *gorm.Association {
+func (m *MockInterface) Association(arg0 string) jorm.Association {
 	ret := m.ctrl.Call(m, "Association", arg0)
-	ret0, _ := ret[0].(*gorm.Association)
+	ret0, _ := ret[0].(jorm.Association)
 	return ret0
 }
 
@@ -180,9 +180,9 @@ func (mr *MockInterfaceMockRecorder) BlockGlobalUpdate(arg0 interface{}) *gomock
 }
 
 // Callback mocks base method
-func (m *MockInterface) Callback() *gorm.Callback {
+func (m *MockInterface) Callback() jorm.Callback {
 	ret := m.ctrl.Call(m, "Callback")
-	ret0, _ := ret[0].(*gorm.Callback)
+	ret0, _ := ret[0].(jorm.Callback)
 	return ret0
 }
 
@@ -216,9 +216,9 @@ func (mr *MockInterfaceMockRecorder) Commit() *gomock.Call {
 }
 
 // CommonDB mocks base method
-func (m *MockInterface) CommonDB() gorm.SQLCommon {
+func (m *MockInterface) CommonDB() jorm.SQLCommon {
 	ret := m.ctrl.Call(m, "CommonDB")
-	ret0, _ := ret[0].(gorm.SQLCommon)
+	ret0, _ := ret[0].(jorm.SQLCommon)
 	return ret0
 }
 
@@ -309,9 +309,9 @@ func (mr *MockInterfaceMockRecorder) Delete(arg0 interface{}, arg1 ...interface{
 }
 
 // Dialect mocks base method
-func (m *MockInterface) Dialect() gorm.Dialect {
+func (m *MockInterface) Dialect() jorm.Dialect {
 	ret := m.ctrl.Call(m, "Dialect")
-	ret0, _ := ret[0].(gorm.Dialect)
+	ret0, _ := ret[0].(jorm.Dialect)
 	return ret0
 }
 
@@ -461,6 +461,30 @@ func (mr *MockInterfaceMockRecorder) FirstOrInit(arg0 interface{}, arg1 ...inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FirstOrInit", reflect.TypeOf((*MockInterface)(nil).FirstOrInit), varargs...)
 }
 
+// ForShare mocks base method
+func (m *MockInterface) ForShare() jorm.Interface {
+	ret := m.ctrl.Call(m, "ForShare")
+	ret0, _ := ret[0].(jorm.Interface)
+	return ret0
+}
+
+// ForShare indicates an expected call of ForShare
+func (mr *MockInterfaceMockRecorder) ForShare() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForShare", reflect.TypeOf((*MockInterface)(nil).ForShare))
+}
+
+// ForUpdate mocks base method
+func (m *MockInterface) ForUpdate() jorm.Interface {
+	ret := m.ctrl.Call(m, "ForUpdate")
+	ret0, _ := ret[0].(jorm.Interface)
+	return ret0
+}
+
+// ForUpdate indicates an expected call of ForUpdate
+func (mr *MockInterfaceMockRecorder) ForUpdate() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForUpdate", reflect.TypeOf((*MockInterface)(nil).ForUpdate))
+}
+
 // Get mocks base method
 func (m *MockInterface) Get(arg0 string) (interface{}, bool) {
 	ret := m.ctrl.Call(m, "Get", arg0)
@@ -669,6 +693,18 @@ func (mr *MockInterfaceMockRecorder) NewRecord(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewRecord", reflect.TypeOf((*MockInterface)(nil).NewRecord), arg0)
 }
 
+// NoWait mocks base method
+func (m *MockInterface) NoWait() jorm.Interface {
+	ret := m.ctrl.Call(m, "NoWait")
+	ret0, _ := ret[0].(jorm.Interface)
+	return ret0
+}
+
+// NoWait indicates an expected call of NoWait
+func (mr *MockInterfaceMockRecorder) NoWait() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NoWait", reflect.TypeOf((*MockInterface)(nil).NoWait))
+}
+
 // Not mocks base method
 func (m *MockInterface) Not(arg0 interface{}, arg1 ...interface{}) jorm.Interface {
 	varargs := []interface{}{arg0}
@@ -908,6 +944,18 @@ func (mr *MockInterfaceMockRecorder) Save(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockInterface)(nil).Save), arg0)
 }
 
+// SavepointName mocks base method
+func (m *MockInterface) SavepointName() string {
+	ret := m.ctrl.Call(m, "SavepointName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SavepointName indicates an expected call of SavepointName
+func (mr *MockInterfaceMockRecorder) SavepointName() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavepointName", reflect.TypeOf((*MockInterface)(nil).SavepointName))
+}
+
 // Scan mocks base method
 func (m *MockInterface) Scan(arg0 interface{}) jorm.Interface {
 	ret := m.ctrl.Call(m, "Scan", arg0)
@@ -921,7 +969,7 @@ func (mr *MockInterfaceMockRecorder) Scan(arg0 interface{}) *gomock.Call {
 }
 
 // ScanRows mocks base method
-func (m *MockInterface) ScanRows(arg0 *sql.Rows, arg1 interface{}) error {
+func (m *MockInterface) ScanRows(arg0 jorm.Rows, arg1 interface{}) error {
 	ret := m.ctrl.Call(m, "ScanRows", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
@@ -1007,6 +1055,18 @@ func (mr *MockInterfaceMockRecorder) SingularTable(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SingularTable", reflect.TypeOf((*MockInterface)(nil).SingularTable), arg0)
 }
 
+// SkipLocked mocks base method
+func (m *MockInterface) SkipLocked() jorm.Interface {
+	ret := m.ctrl.Call(m, "SkipLocked")
+	ret0, _ := ret[0].(jorm.Interface)
+	return ret0
+}
+
+// SkipLocked indicates an expected call of SkipLocked
+func (mr *MockInterfaceMockRecorder) SkipLocked() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipLocked", reflect.TypeOf((*MockInterface)(nil).SkipLocked))
+}
+
 // Table mocks base method
 func (m *MockInterface) Table(arg0 string) jorm.Interface {
 	ret := m.ctrl.Call(m, "Table", arg0)
@@ -1036,6 +1096,44 @@ func (mr *MockInterfaceMockRecorder) Take(arg0 interface{}, arg1 ...interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockInterface)(nil).Take), varargs...)
 }
 
+// ToSQL mocks base method
+func (m *MockInterface) ToSQL(arg0 func(jorm.Interface) jorm.Interface) (string, []interface{}, error) {
+	ret := m.ctrl.Call(m, "ToSQL", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]interface{})
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ToSQL indicates an expected call of ToSQL
+func (mr *MockInterfaceMockRecorder) ToSQL(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToSQL", reflect.TypeOf((*MockInterface)(nil).ToSQL), arg0)
+}
+
+// Transaction mocks base method
+func (m *MockInterface) Transaction(arg0 context.Context, arg1 func(jorm.Interface) error) error {
+	ret := m.ctrl.Call(m, "Transaction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction
+func (mr *MockInterfaceMockRecorder) Transaction(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockInterface)(nil).Transaction), arg0, arg1)
+}
+
+// TxDepth mocks base method
+func (m *MockInterface) TxDepth() int {
+	ret := m.ctrl.Call(m, "TxDepth")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// TxDepth indicates an expected call of TxDepth
+func (mr *MockInterfaceMockRecorder) TxDepth() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TxDepth", reflect.TypeOf((*MockInterface)(nil).TxDepth))
+}
+
 // Unscoped mocks base method
 func (m *MockInterface) Unscoped() jorm.Interface {
 	ret := m.ctrl.Call(m, "Unscoped")
@@ -1109,6 +1207,18 @@ func (mr *MockInterfaceMockRecorder) Updates(arg0 interface{}, arg1 ...interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Updates", reflect.TypeOf((*MockInterface)(nil).Updates), varargs...)
 }
 
+// UsePrimary mocks base method
+func (m *MockInterface) UsePrimary() jorm.Interface {
+	ret := m.ctrl.Call(m, "UsePrimary")
+	ret0, _ := ret[0].(jorm.Interface)
+	return ret0
+}
+
+// UsePrimary indicates an expected call of UsePrimary
+func (mr *MockInterfaceMockRecorder) UsePrimary() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePrimary", reflect.TypeOf((*MockInterface)(nil).UsePrimary))
+}
+
 // Value mocks base method
 func (m *MockInterface) Value() interface{} {
 	ret := m.ctrl.Call(m, "Value")
@@ -1150,6 +1260,30 @@ func (mr *MockInterfaceMockRecorder) WithContext(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockInterface)(nil).WithContext), arg0)
 }
 
+// WithLogger mocks base method
+func (m *MockInterface) WithLogger(arg0 jorm.Logger) jorm.Interface {
+	ret := m.ctrl.Call(m, "WithLogger", arg0)
+	ret0, _ := ret[0].(jorm.Interface)
+	return ret0
+}
+
+// WithLogger indicates an expected call of WithLogger
+func (mr *MockInterfaceMockRecorder) WithLogger(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLogger", reflect.TypeOf((*MockInterface)(nil).WithLogger), arg0)
+}
+
+// WithoutTenantScope mocks base method
+func (m *MockInterface) WithoutTenantScope() jorm.Interface {
+	ret := m.ctrl.Call(m, "WithoutTenantScope")
+	ret0, _ := ret[0].(jorm.Interface)
+	return ret0
+}
+
+// WithoutTenantScope indicates an expected call of WithoutTenantScope
+func (mr *MockInterfaceMockRecorder) WithoutTenantScope() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithoutTenantScope", reflect.TypeOf((*MockInterface)(nil).WithoutTenantScope))
+}
+
 // MockRow is a mock of Row interface
 type MockRow struct {
 	ctrl     *gomock.Controller
@@ -1301,3 +1435,579 @@ func (m *MockRows) Scan(arg0 ...interface{}) error {
 func (mr *MockRowsMockRecorder) Scan(arg0 ...interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockRows)(nil).Scan), arg0...)
 }
+
+// MockSQLCommon is a mock of SQLCommon interface
+type MockSQLCommon struct {
+	ctrl     *gomock.Controller
+	recorder *MockSQLCommonMockRecorder
+}
+
+// MockSQLCommonMockRecorder is the mock recorder for MockSQLCommon
+type MockSQLCommonMockRecorder struct {
+	mock *MockSQLCommon
+}
+
+// NewMockSQLCommon creates a new mock instance
+func NewMockSQLCommon(ctrl *gomock.Controller) *MockSQLCommon {
+	mock := &MockSQLCommon{ctrl: ctrl}
+	mock.recorder = &MockSQLCommonMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSQLCommon) EXPECT() *MockSQLCommonMockRecorder {
+	return m.recorder
+}
+
+// Exec mocks base method
+func (m *MockSQLCommon) Exec(arg0 string, arg1 ...interface{}) (sql.Result, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exec", varargs...)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec
+func (mr *MockSQLCommonMockRecorder) Exec(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockSQLCommon)(nil).Exec), varargs...)
+}
+
+// Prepare mocks base method
+func (m *MockSQLCommon) Prepare(arg0 string) (*sql.Stmt, error) {
+	ret := m.ctrl.Call(m, "Prepare", arg0)
+	ret0, _ := ret[0].(*sql.Stmt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prepare indicates an expected call of Prepare
+func (mr *MockSQLCommonMockRecorder) Prepare(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepare", reflect.TypeOf((*MockSQLCommon)(nil).Prepare), arg0)
+}
+
+// Query mocks base method
+func (m *MockSQLCommon) Query(arg0 string, arg1 ...interface{}) (*sql.Rows, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Query", varargs...)
+	ret0, _ := ret[0].(*sql.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query
+func (mr *MockSQLCommonMockRecorder) Query(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockSQLCommon)(nil).Query), varargs...)
+}
+
+// QueryRow mocks base method
+func (m *MockSQLCommon) QueryRow(arg0 string, arg1 ...interface{}) *sql.Row {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryRow", varargs...)
+	ret0, _ := ret[0].(*sql.Row)
+	return ret0
+}
+
+// QueryRow indicates an expected call of QueryRow
+func (mr *MockSQLCommonMockRecorder) QueryRow(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockSQLCommon)(nil).QueryRow), varargs...)
+}
+
+// MockAssociation is a mock of Association interface
+type MockAssociation struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssociationMockRecorder
+}
+
+// MockAssociationMockRecorder is the mock recorder for MockAssociation
+type MockAssociationMockRecorder struct {
+	mock *MockAssociation
+}
+
+// NewMockAssociation creates a new mock instance
+func NewMockAssociation(ctrl *gomock.Controller) *MockAssociation {
+	mock := &MockAssociation{ctrl: ctrl}
+	mock.recorder = &MockAssociationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssociation) EXPECT() *MockAssociationMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method
+func (m *MockAssociation) Append(arg0 ...interface{}) jorm.Association {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Append", varargs...)
+	ret0, _ := ret[0].(jorm.Association)
+	return ret0
+}
+
+// Append indicates an expected call of Append
+func (mr *MockAssociationMockRecorder) Append(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockAssociation)(nil).Append), arg0...)
+}
+
+// Clear mocks base method
+func (m *MockAssociation) Clear() jorm.Association {
+	ret := m.ctrl.Call(m, "Clear")
+	ret0, _ := ret[0].(jorm.Association)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear
+func (mr *MockAssociationMockRecorder) Clear() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockAssociation)(nil).Clear))
+}
+
+// Count mocks base method
+func (m *MockAssociation) Count() int {
+	ret := m.ctrl.Call(m, "Count")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Count indicates an expected call of Count
+func (mr *MockAssociationMockRecorder) Count() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockAssociation)(nil).Count))
+}
+
+// Delete mocks base method
+func (m *MockAssociation) Delete(arg0 ...interface{}) jorm.Association {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(jorm.Association)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockAssociationMockRecorder) Delete(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAssociation)(nil).Delete), arg0...)
+}
+
+// Error mocks base method
+func (m *MockAssociation) Error() error {
+	ret := m.ctrl.Call(m, "Error")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Error indicates an expected call of Error
+func (mr *MockAssociationMockRecorder) Error() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockAssociation)(nil).Error))
+}
+
+// Find mocks base method
+func (m *MockAssociation) Find(arg0 interface{}) jorm.Association {
+	ret := m.ctrl.Call(m, "Find", arg0)
+	ret0, _ := ret[0].(jorm.Association)
+	return ret0
+}
+
+// Find indicates an expected call of Find
+func (mr *MockAssociationMockRecorder) Find(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAssociation)(nil).Find), arg0)
+}
+
+// Replace mocks base method
+func (m *MockAssociation) Replace(arg0 ...interface{}) jorm.Association {
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Replace", varargs...)
+	ret0, _ := ret[0].(jorm.Association)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace
+func (mr *MockAssociationMockRecorder) Replace(arg0 ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockAssociation)(nil).Replace), arg0...)
+}
+
+// MockCallback is a mock of Callback interface
+type MockCallback struct {
+	ctrl     *gomock.Controller
+	recorder *MockCallbackMockRecorder
+}
+
+// MockCallbackMockRecorder is the mock recorder for MockCallback
+type MockCallbackMockRecorder struct {
+	mock *MockCallback
+}
+
+// NewMockCallback creates a new mock instance
+func NewMockCallback(ctrl *gomock.Controller) *MockCallback {
+	mock := &MockCallback{ctrl: ctrl}
+	mock.recorder = &MockCallbackMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCallback) EXPECT() *MockCallbackMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockCallback) Create() jorm.CallbackProcessor {
+	ret := m.ctrl.Call(m, "Create")
+	ret0, _ := ret[0].(jorm.CallbackProcessor)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCallbackMockRecorder) Create() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCallback)(nil).Create))
+}
+
+// Delete mocks base method
+func (m *MockCallback) Delete() jorm.CallbackProcessor {
+	ret := m.ctrl.Call(m, "Delete")
+	ret0, _ := ret[0].(jorm.CallbackProcessor)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockCallbackMockRecorder) Delete() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCallback)(nil).Delete))
+}
+
+// Query mocks base method
+func (m *MockCallback) Query() jorm.CallbackProcessor {
+	ret := m.ctrl.Call(m, "Query")
+	ret0, _ := ret[0].(jorm.CallbackProcessor)
+	return ret0
+}
+
+// Query indicates an expected call of Query
+func (mr *MockCallbackMockRecorder) Query() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockCallback)(nil).Query))
+}
+
+// RowQuery mocks base method
+func (m *MockCallback) RowQuery() jorm.CallbackProcessor {
+	ret := m.ctrl.Call(m, "RowQuery")
+	ret0, _ := ret[0].(jorm.CallbackProcessor)
+	return ret0
+}
+
+// RowQuery indicates an expected call of RowQuery
+func (mr *MockCallbackMockRecorder) RowQuery() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RowQuery", reflect.TypeOf((*MockCallback)(nil).RowQuery))
+}
+
+// Update mocks base method
+func (m *MockCallback) Update() jorm.CallbackProcessor {
+	ret := m.ctrl.Call(m, "Update")
+	ret0, _ := ret[0].(jorm.CallbackProcessor)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCallbackMockRecorder) Update() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCallback)(nil).Update))
+}
+
+// MockCallbackProcessor is a mock of CallbackProcessor interface
+type MockCallbackProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockCallbackProcessorMockRecorder
+}
+
+// MockCallbackProcessorMockRecorder is the mock recorder for MockCallbackProcessor
+type MockCallbackProcessorMockRecorder struct {
+	mock *MockCallbackProcessor
+}
+
+// NewMockCallbackProcessor creates a new mock instance
+func NewMockCallbackProcessor(ctrl *gomock.Controller) *MockCallbackProcessor {
+	mock := &MockCallbackProcessor{ctrl: ctrl}
+	mock.recorder = &MockCallbackProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCallbackProcessor) EXPECT() *MockCallbackProcessorMockRecorder {
+	return m.recorder
+}
+
+// After mocks base method
+func (m *MockCallbackProcessor) After(arg0 string) jorm.CallbackProcessor {
+	ret := m.ctrl.Call(m, "After", arg0)
+	ret0, _ := ret[0].(jorm.CallbackProcessor)
+	return ret0
+}
+
+// After indicates an expected call of After
+func (mr *MockCallbackProcessorMockRecorder) After(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "After", reflect.TypeOf((*MockCallbackProcessor)(nil).After), arg0)
+}
+
+// Before mocks base method
+func (m *MockCallbackProcessor) Before(arg0 string) jorm.CallbackProcessor {
+	ret := m.ctrl.Call(m, "Before", arg0)
+	ret0, _ := ret[0].(jorm.CallbackProcessor)
+	return ret0
+}
+
+// Before indicates an expected call of Before
+func (mr *MockCallbackProcessorMockRecorder) Before(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Before", reflect.TypeOf((*MockCallbackProcessor)(nil).Before), arg0)
+}
+
+// Get mocks base method
+func (m *MockCallbackProcessor) Get(arg0 string) func(*gorm.Scope) {
+	ret := m.ctrl.Call(m, "Get", arg0)
+	ret0, _ := ret[0].(func(*gorm.Scope))
+	return ret0
+}
+
+// Get indicates an expected call of Get
+func (mr *MockCallbackProcessorMockRecorder) Get(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCallbackProcessor)(nil).Get), arg0)
+}
+
+// Register mocks base method
+func (m *MockCallbackProcessor) Register(arg0 string, arg1 func(*gorm.Scope)) {
+	m.ctrl.Call(m, "Register", arg0, arg1)
+}
+
+// Register indicates an expected call of Register
+func (mr *MockCallbackProcessorMockRecorder) Register(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockCallbackProcessor)(nil).Register), arg0, arg1)
+}
+
+// Remove mocks base method
+func (m *MockCallbackProcessor) Remove(arg0 string) {
+	m.ctrl.Call(m, "Remove", arg0)
+}
+
+// Remove indicates an expected call of Remove
+func (mr *MockCallbackProcessorMockRecorder) Remove(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockCallbackProcessor)(nil).Remove), arg0)
+}
+
+// Replace mocks base method
+func (m *MockCallbackProcessor) Replace(arg0 string, arg1 func(*gorm.Scope)) {
+	m.ctrl.Call(m, "Replace", arg0, arg1)
+}
+
+// Replace indicates an expected call of Replace
+func (mr *MockCallbackProcessorMockRecorder) Replace(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockCallbackProcessor)(nil).Replace), arg0, arg1)
+}
+
+// MockDialect is a mock of Dialect interface
+type MockDialect struct {
+	ctrl     *gomock.Controller
+	recorder *MockDialectMockRecorder
+}
+
+// MockDialectMockRecorder is the mock recorder for MockDialect
+type MockDialectMockRecorder struct {
+	mock *MockDialect
+}
+
+// NewMockDialect creates a new mock instance
+func NewMockDialect(ctrl *gomock.Controller) *MockDialect {
+	mock := &MockDialect{ctrl: ctrl}
+	mock.recorder = &MockDialectMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDialect) EXPECT() *MockDialectMockRecorder {
+	return m.recorder
+}
+
+// BindVar mocks base method
+func (m *MockDialect) BindVar(arg0 int) string {
+	ret := m.ctrl.Call(m, "BindVar", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BindVar indicates an expected call of BindVar
+func (mr *MockDialectMockRecorder) BindVar(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindVar", reflect.TypeOf((*MockDialect)(nil).BindVar), arg0)
+}
+
+// BuildKeyName mocks base method
+func (m *MockDialect) BuildKeyName(arg0, arg1 string, arg2 ...string) string {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BuildKeyName", varargs...)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BuildKeyName indicates an expected call of BuildKeyName
+func (mr *MockDialectMockRecorder) BuildKeyName(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildKeyName", reflect.TypeOf((*MockDialect)(nil).BuildKeyName), varargs...)
+}
+
+// CurrentDatabase mocks base method
+func (m *MockDialect) CurrentDatabase() string {
+	ret := m.ctrl.Call(m, "CurrentDatabase")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CurrentDatabase indicates an expected call of CurrentDatabase
+func (mr *MockDialectMockRecorder) CurrentDatabase() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentDatabase", reflect.TypeOf((*MockDialect)(nil).CurrentDatabase))
+}
+
+// DataTypeOf mocks base method
+func (m *MockDialect) DataTypeOf(arg0 *gorm.StructField) string {
+	ret := m.ctrl.Call(m, "DataTypeOf", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DataTypeOf indicates an expected call of DataTypeOf
+func (mr *MockDialectMockRecorder) DataTypeOf(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataTypeOf", reflect.TypeOf((*MockDialect)(nil).DataTypeOf), arg0)
+}
+
+// GetName mocks base method
+func (m *MockDialect) GetName() string {
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName
+func (mr *MockDialectMockRecorder) GetName() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockDialect)(nil).GetName))
+}
+
+// HasColumn mocks base method
+func (m *MockDialect) HasColumn(arg0, arg1 string) bool {
+	ret := m.ctrl.Call(m, "HasColumn", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasColumn indicates an expected call of HasColumn
+func (mr *MockDialectMockRecorder) HasColumn(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasColumn", reflect.TypeOf((*MockDialect)(nil).HasColumn), arg0, arg1)
+}
+
+// HasForeignKey mocks base method
+func (m *MockDialect) HasForeignKey(arg0, arg1 string) bool {
+	ret := m.ctrl.Call(m, "HasForeignKey", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasForeignKey indicates an expected call of HasForeignKey
+func (mr *MockDialectMockRecorder) HasForeignKey(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasForeignKey", reflect.TypeOf((*MockDialect)(nil).HasForeignKey), arg0, arg1)
+}
+
+// HasIndex mocks base method
+func (m *MockDialect) HasIndex(arg0, arg1 string) bool {
+	ret := m.ctrl.Call(m, "HasIndex", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasIndex indicates an expected call of HasIndex
+func (mr *MockDialectMockRecorder) HasIndex(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasIndex", reflect.TypeOf((*MockDialect)(nil).HasIndex), arg0, arg1)
+}
+
+// HasTable mocks base method
+func (m *MockDialect) HasTable(arg0 string) bool {
+	ret := m.ctrl.Call(m, "HasTable", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasTable indicates an expected call of HasTable
+func (mr *MockDialectMockRecorder) HasTable(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTable", reflect.TypeOf((*MockDialect)(nil).HasTable), arg0)
+}
+
+// LastInsertIDReturningSuffix mocks base method
+func (m *MockDialect) LastInsertIDReturningSuffix(arg0, arg1 string) string {
+	ret := m.ctrl.Call(m, "LastInsertIDReturningSuffix", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// LastInsertIDReturningSuffix indicates an expected call of LastInsertIDReturningSuffix
+func (mr *MockDialectMockRecorder) LastInsertIDReturningSuffix(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastInsertIDReturningSuffix", reflect.TypeOf((*MockDialect)(nil).LastInsertIDReturningSuffix), arg0, arg1)
+}
+
+// ModifyColumn mocks base method
+func (m *MockDialect) ModifyColumn(arg0, arg1, arg2 string) error {
+	ret := m.ctrl.Call(m, "ModifyColumn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyColumn indicates an expected call of ModifyColumn
+func (mr *MockDialectMockRecorder) ModifyColumn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyColumn", reflect.TypeOf((*MockDialect)(nil).ModifyColumn), arg0, arg1, arg2)
+}
+
+// Quote mocks base method
+func (m *MockDialect) Quote(arg0 string) string {
+	ret := m.ctrl.Call(m, "Quote", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Quote indicates an expected call of Quote
+func (mr *MockDialectMockRecorder) Quote(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockDialect)(nil).Quote), arg0)
+}
+
+// RemoveIndex mocks base method
+func (m *MockDialect) RemoveIndex(arg0, arg1 string) error {
+	ret := m.ctrl.Call(m, "RemoveIndex", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveIndex indicates an expected call of RemoveIndex
+func (mr *MockDialectMockRecorder) RemoveIndex(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveIndex", reflect.TypeOf((*MockDialect)(nil).RemoveIndex), arg0, arg1)
+}
+
+// SelectFromDummyTable mocks base method
+func (m *MockDialect) SelectFromDummyTable() string {
+	ret := m.ctrl.Call(m, "SelectFromDummyTable")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SelectFromDummyTable indicates an expected call of SelectFromDummyTable
+func (mr *MockDialectMockRecorder) SelectFromDummyTable() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectFromDummyTable", reflect.TypeOf((*MockDialect)(nil).SelectFromDummyTable))
+}
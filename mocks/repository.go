@@ -0,0 +1,125 @@
+// MockRepository is maintained by hand as mockgen cannot generate mocks of generic interfaces,
+// it follows the layout of the generated mocks so it is used the same way
+
+package mocks
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	jorm "github.com/jloom6/jorm"
+	reflect "reflect"
+)
+
+// MockRepository is a mock of Repository interface
+type MockRepository[T any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder[T]
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository
+type MockRepositoryMockRecorder[T any] struct {
+	mock *MockRepository[T]
+}
+
+// NewMockRepository creates a new mock instance
+func NewMockRepository[T any](ctrl *gomock.Controller) *MockRepository[T] {
+	mock := &MockRepository[T]{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepository[T]) EXPECT() *MockRepositoryMockRecorder[T] {
+	return m.recorder
+}
+
+// Count mocks base method
+func (m *MockRepository[T]) Count(arg0 context.Context, arg1 jorm.ListOptions) (int64, error) {
+	ret := m.ctrl.Call(m, "Count", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockRepositoryMockRecorder[T]) Count(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepository[T])(nil).Count), arg0, arg1)
+}
+
+// Create mocks base method
+func (m *MockRepository[T]) Create(arg0 context.Context, arg1 *T) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockRepositoryMockRecorder[T]) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository[T])(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockRepository[T]) Delete(arg0 context.Context, arg1 *T) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockRepositoryMockRecorder[T]) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository[T])(nil).Delete), arg0, arg1)
+}
+
+// Exists mocks base method
+func (m *MockRepository[T]) Exists(arg0 context.Context, arg1 interface{}) (bool, error) {
+	ret := m.ctrl.Call(m, "Exists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists
+func (mr *MockRepositoryMockRecorder[T]) Exists(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockRepository[T])(nil).Exists), arg0, arg1)
+}
+
+// Get mocks base method
+func (m *MockRepository[T]) Get(arg0 context.Context, arg1 interface{}) (T, error) {
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockRepositoryMockRecorder[T]) Get(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository[T])(nil).Get), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockRepository[T]) List(arg0 context.Context, arg1 jorm.ListOptions) ([]T, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockRepositoryMockRecorder[T]) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository[T])(nil).List), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockRepository[T]) Update(arg0 context.Context, arg1 *T) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockRepositoryMockRecorder[T]) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository[T])(nil).Update), arg0, arg1)
+}
+
+// compile time check that the mock implements the interface
+var _ jorm.Repository[struct{}] = (*MockRepository[struct{}])(nil)
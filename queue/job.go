@@ -0,0 +1,36 @@
+package queue
+
+import "time"
+
+// Statuses of a job
+const (
+	// StatusPending is a job waiting for its RunAt to be claimed
+	StatusPending = "pending"
+	// StatusRunning is a job claimed by a worker until its RunAt, when it can be claimed again
+	StatusRunning = "running"
+	// StatusDone is a completed job
+	StatusDone = "done"
+	// StatusDead is a job that failed MaxAttempts times, it stays in the table until it is retried
+	StatusDead = "dead"
+)
+
+// Job is a row of the jobs table
+type Job struct {
+	ID    uint   `gorm:"primary_key"`
+	Queue string `gorm:"size:191;not null;index:idx_jobs_claim"`
+	// Payload is what the job is about, for example JSON
+	Payload []byte
+	// Priority orders the claims of jobs, higher first
+	Priority int    `gorm:"not null"`
+	Status   string `gorm:"size:16;not null;index:idx_jobs_claim"`
+	// RunAt is when a pending job can be claimed, and when a running job can be claimed again if its worker has not
+	// completed it or sent a heartbeat
+	RunAt time.Time `gorm:"not null;index:idx_jobs_claim"`
+	// Attempts is how many times the job has been claimed, a job that fails MaxAttempts times is dead
+	Attempts    int `gorm:"not null"`
+	MaxAttempts int `gorm:"not null"`
+	// LastError is the error of the last failed attempt
+	LastError string `gorm:"type:text"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,355 @@
+// Package queue is a job queue stored in the jobs table of the database of a jorm.Interface. Workers claim jobs with
+// SELECT ... FOR UPDATE SKIP LOCKED so no two workers claim the same job, and a job enqueued in the transaction of
+// the writes it is about exists if and only if they are committed
+//     q := queue.New(db, queue.Options{Name: "emails"})
+//     err := db.Transaction(ctx, func(tx jorm.Interface) error {
+//         if err := tx.Create(&user).Error(); err != nil {
+//             return err
+//         }
+//         return q.With(tx).Enqueue(ctx, &queue.Job{Payload: payload})
+//     })
+//
+//     n, err := q.Process(ctx, 10, func(ctx context.Context, job *queue.Job) error {
+//         return sendEmail(ctx, job.Payload)
+//     })
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jloom6/jorm"
+)
+
+// ErrLostClaim is returned when a worker updates a job it no longer holds the claim of, because its visibility
+// timeout expired and another worker claimed it or it was dead-lettered
+var ErrLostClaim = errors.New("queue: job is no longer claimed")
+
+// maxBackoff is the longest delay of ExponentialBackoff
+const maxBackoff = time.Hour
+
+// Options configures a queue
+type Options struct {
+	// Name is the queue the jobs are enqueued to and claimed from, defaults to "default"
+	Name string
+	// VisibilityTimeout is how long a claimed job is hidden from other workers, a job that is not completed, failed
+	// or sent a heartbeat within it can be claimed again. Defaults to 30 seconds
+	VisibilityTimeout time.Duration
+	// MaxAttempts is how many times a job is claimed before it is dead-lettered, unless the job sets its own,
+	// defaults to 5
+	MaxAttempts int
+	// Backoff returns how long a failed job waits before its next attempt, defaults to ExponentialBackoff
+	Backoff func(attempts int) time.Duration
+}
+
+// ExponentialBackoff waits a second after the first attempt and twice as long after each next one, up to an hour
+func ExponentialBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 13 {
+		return maxBackoff
+	}
+	if d := time.Second << uint(attempts-1); d < maxBackoff {
+		return d
+	}
+	return maxBackoff
+}
+
+// Queue enqueues and claims the jobs of a queue
+type Queue struct {
+	db   jorm.Interface
+	opts Options
+}
+
+// New returns a queue of the jobs table of the db, see Migrate
+func New(db jorm.Interface, opts Options) *Queue {
+	if opts.Name == "" {
+		opts.Name = "default"
+	}
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = 30 * time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = ExponentialBackoff
+	}
+	return &Queue{db: db, opts: opts}
+}
+
+// With returns a copy of the queue using the db, typically a transaction so that jobs are enqueued atomically with
+// its writes
+//     q.With(tx).Enqueue(ctx, &queue.Job{Payload: payload})
+func (q *Queue) With(db jorm.Interface) *Queue {
+	c := *q
+	c.db = db
+	return &c
+}
+
+// Migrate creates or updates the jobs table and its index
+func (q *Queue) Migrate() error {
+	return q.db.AutoMigrate(&Job{}).Error()
+}
+
+// now returns the current time, in UTC so that times written by workers in different time zones compare
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// Enqueue adds the job to the queue, it can be claimed from its RunAt, now if it is zero, in decreasing Priority
+func (q *Queue) Enqueue(ctx context.Context, job *Job) error {
+	job.Queue = q.opts.Name
+	job.Status = StatusPending
+	job.Attempts = 0
+	if job.RunAt.IsZero() {
+		job.RunAt = now()
+	}
+	job.RunAt = job.RunAt.UTC()
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = q.opts.MaxAttempts
+	}
+	return q.db.WithContext(ctx).Create(job).Error()
+}
+
+// Claim claims up to n jobs that can run, by decreasing priority and then in the order they can run, and hides them
+// from other workers for the visibility timeout. Each job must then be completed or failed, and sent heartbeats if
+// it takes longer than the visibility timeout. Jobs whose last attempt timed out are dead-lettered
+func (q *Queue) Claim(ctx context.Context, n int) ([]Job, error) {
+	var claimed []Job
+	err := q.db.Transaction(ctx, func(tx jorm.Interface) error {
+		claimed = nil
+		start := now()
+
+		var jobs []Job
+		err := tx.SkipLocked().
+			Where("queue = ? AND status IN (?) AND run_at <= ?", q.opts.Name, []string{StatusPending, StatusRunning}, start).
+			Order("priority DESC, run_at, id").
+			Limit(n).
+			Find(&jobs).Error()
+		if err != nil {
+			return err
+		}
+
+		var ids []uint
+		for i := range jobs {
+			job := &jobs[i]
+			if job.Status == StatusRunning && job.Attempts >= job.MaxAttempts {
+				// the worker of the last attempt neither completed nor failed it within the visibility timeout
+				err := q.update(tx, job, map[string]interface{}{"status": StatusDead, "last_error": "visibility timeout expired"})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			ids = append(ids, job.ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		until := start.Add(q.opts.VisibilityTimeout)
+		err = tx.Model(&Job{}).Where("id IN (?)", ids).Updates(map[string]interface{}{
+			"status":   StatusRunning,
+			"attempts": gorm.Expr("attempts + 1"),
+			"run_at":   until,
+		}).Error()
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			if job.Status == StatusRunning && job.Attempts >= job.MaxAttempts {
+				continue
+			}
+			job.Status = StatusRunning
+			job.Attempts++
+			job.RunAt = until
+			claimed = append(claimed, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// Heartbeat hides a claimed job from other workers for another visibility timeout, ErrLostClaim if the job is no
+// longer claimed by this attempt
+func (q *Queue) Heartbeat(ctx context.Context, job *Job) error {
+	until := now().Add(q.opts.VisibilityTimeout)
+	if err := q.update(q.db.WithContext(ctx), job, map[string]interface{}{"run_at": until}); err != nil {
+		return err
+	}
+	job.RunAt = until
+	return nil
+}
+
+// Complete marks a claimed job as done
+func (q *Queue) Complete(ctx context.Context, job *Job) error {
+	if err := q.update(q.db.WithContext(ctx), job, map[string]interface{}{"status": StatusDone}); err != nil {
+		return err
+	}
+	job.Status = StatusDone
+	return nil
+}
+
+// Fail records the error of a claimed job and retries it after the backoff of its attempts, or dead-letters it once
+// it has been attempted MaxAttempts times. A nil cause records no error
+func (q *Queue) Fail(ctx context.Context, job *Job, cause error) error {
+	var lastError string
+	if cause != nil {
+		lastError = cause.Error()
+	}
+	values := map[string]interface{}{"status": StatusPending, "last_error": lastError}
+	if job.Attempts >= job.MaxAttempts {
+		values["status"] = StatusDead
+	} else {
+		values["run_at"] = now().Add(q.opts.Backoff(job.Attempts))
+	}
+	if err := q.update(q.db.WithContext(ctx), job, values); err != nil {
+		return err
+	}
+
+	job.Status = values["status"].(string)
+	job.LastError = lastError
+	if runAt, ok := values["run_at"].(time.Time); ok {
+		job.RunAt = runAt
+	}
+	return nil
+}
+
+// update updates a claimed job, ErrLostClaim if it is no longer claimed by the attempt of the job
+func (q *Queue) update(db jorm.Interface, job *Job, values map[string]interface{}) error {
+	claim := db.Model(&Job{}).Where("id = ? AND status = ? AND attempts = ?", job.ID, StatusRunning, job.Attempts)
+	result := claim.Updates(values)
+	if err := result.Error(); err != nil {
+		return err
+	}
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	// MySQL reports no rows affected when the values are unchanged, such as two heartbeats within a second
+	var count int
+	if err := claim.Count(&count).Error(); err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrLostClaim
+	}
+	return nil
+}
+
+// Dead returns up to limit dead-lettered jobs of the queue, the most recently failed first
+func (q *Queue) Dead(ctx context.Context, limit int) ([]Job, error) {
+	var jobs []Job
+	err := q.db.WithContext(ctx).
+		Where("queue = ? AND status = ?", q.opts.Name, StatusDead).
+		Order("updated_at DESC, id DESC").
+		Limit(limit).
+		Find(&jobs).Error()
+	return jobs, err
+}
+
+// Retry enqueues a dead-lettered job again with no attempts, jorm.ErrNotFound if the queue has no such dead job
+func (q *Queue) Retry(ctx context.Context, id uint) error {
+	result := q.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND queue = ? AND status = ?", id, q.opts.Name, StatusDead).
+		Updates(map[string]interface{}{"status": StatusPending, "attempts": 0, "run_at": now()})
+	if err := result.Error(); err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return jorm.ErrNotFound
+	}
+	return nil
+}
+
+// Process claims up to n jobs and runs handler on each in turn, sending heartbeats while it runs. A job is completed
+// if handler returns nil and failed with its error otherwise, and skipped with ErrLostClaim if another worker claimed
+// it while it waited its turn. It returns how many jobs were claimed and the first error completing or failing them
+//     for ctx.Err() == nil {
+//         if n, err := q.Process(ctx, 10, handle); n == 0 || err != nil {
+//             time.Sleep(time.Second)
+//         }
+//     }
+func (q *Queue) Process(ctx context.Context, n int, handler func(ctx context.Context, job *Job) error) (int, error) {
+	jobs, err := q.Claim(ctx, n)
+	if err != nil {
+		return 0, err
+	}
+
+	var firstErr error
+	for i := range jobs {
+		job := &jobs[i]
+		if err := q.process(ctx, job, handler); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return len(jobs), firstErr
+}
+
+// process runs handler on a claimed job while sending heartbeats, and completes or fails it. The claim is renewed
+// before handler runs, as it may have expired and been claimed by another worker while the job waited for the
+// previous jobs of its batch, in which case handler does not run. When a heartbeat finds the claim lost the context
+// of handler is cancelled and the job is neither completed nor failed
+func (q *Queue) process(ctx context.Context, job *Job, handler func(ctx context.Context, job *Job) error) error {
+	if err := q.Heartbeat(ctx, job); err != nil {
+		return err
+	}
+
+	handlerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		lost bool
+		done = make(chan struct{})
+	)
+	heartbeat := *job
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(q.opts.VisibilityTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				// other errors are retried on the next tick, Complete and Fail find the claim lost if it expires
+				lost = errors.Is(q.Heartbeat(ctx, &heartbeat), ErrLostClaim)
+				mu.Unlock()
+				if lost {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	handlerErr := handler(handlerCtx, job)
+	close(done)
+	wg.Wait()
+
+	mu.Lock()
+	job.RunAt = heartbeat.RunAt
+	claimLost := lost
+	mu.Unlock()
+	if claimLost {
+		return ErrLostClaim
+	}
+	if handlerErr != nil {
+		return q.Fail(ctx, job, handlerErr)
+	}
+	return q.Complete(ctx, job)
+}
@@ -0,0 +1,286 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/queue"
+)
+
+type account struct {
+	ID   uint
+	Name string
+}
+
+var errBoom = errors.New("boom")
+
+// open returns a SQLite db with the jobs and accounts tables and a queue of it
+func open(t *testing.T, opts queue.Options) (jorm.Interface, *queue.Queue) {
+	t.Helper()
+	g, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "queue.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Close() })
+
+	db := jorm.NewDB(g)
+	q := queue.New(db, opts)
+	if err := q.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&account{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return db, q
+}
+
+func TestEnqueueInTransaction(t *testing.T) {
+	ctx := context.Background()
+	db, q := open(t, queue.Options{})
+
+	err := db.Transaction(ctx, func(tx jorm.Interface) error {
+		if err := tx.Create(&account{Name: "a"}).Error(); err != nil {
+			return err
+		}
+		if err := q.With(tx).Enqueue(ctx, &queue.Job{Payload: []byte("rolled back")}); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if err != errBoom {
+		t.Fatal(err)
+	}
+	err = db.Transaction(ctx, func(tx jorm.Interface) error {
+		if err := tx.Create(&account{Name: "b"}).Error(); err != nil {
+			return err
+		}
+		return q.With(tx).Enqueue(ctx, &queue.Job{Payload: []byte("committed")})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := q.Claim(ctx, 5)
+	if err != nil || len(jobs) != 1 || string(jobs[0].Payload) != "committed" {
+		t.Fatalf("Claim = %v %v", jobs, err)
+	}
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	_, q := open(t, queue.Options{})
+
+	for _, job := range []*queue.Job{{Payload: []byte("low")}, {Payload: []byte("high"), Priority: 5}} {
+		if err := q.Enqueue(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, err := q.Claim(ctx, 1)
+	if err != nil || len(jobs) != 1 || string(jobs[0].Payload) != "high" || jobs[0].Attempts != 1 {
+		t.Fatalf("first Claim = %v %v", jobs, err)
+	}
+	high := jobs[0]
+	for i := 0; i < 2; i++ {
+		if err := q.Heartbeat(ctx, &high); err != nil {
+			t.Fatalf("Heartbeat = %v", err)
+		}
+	}
+
+	jobs, err = q.Claim(ctx, 5)
+	if err != nil || len(jobs) != 1 || string(jobs[0].Payload) != "low" {
+		t.Fatalf("second Claim = %v %v", jobs, err)
+	}
+	if err := q.Complete(ctx, &high); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Complete(ctx, &high); err != queue.ErrLostClaim {
+		t.Fatalf("Complete of a completed job = %v", err)
+	}
+}
+
+func TestFailAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	_, q := open(t, queue.Options{
+		VisibilityTimeout: 100 * time.Millisecond,
+		MaxAttempts:       2,
+		Backoff:           func(int) time.Duration { return 0 },
+	})
+	if err := q.Enqueue(ctx, &queue.Job{}); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := q.Claim(ctx, 5)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("Claim = %v %v", jobs, err)
+	}
+	job := jobs[0]
+	if err := q.Fail(ctx, &job, errBoom); err != nil || job.Status != queue.StatusPending {
+		t.Fatalf("Fail of the first attempt = %+v %v", job, err)
+	}
+
+	jobs, err = q.Claim(ctx, 5)
+	if err != nil || len(jobs) != 1 || jobs[0].Attempts != 2 {
+		t.Fatalf("Claim of the last attempt = %v %v", jobs, err)
+	}
+	job = jobs[0]
+
+	// the last attempt times out so the next claim dead-letters it
+	time.Sleep(150 * time.Millisecond)
+	if jobs, err = q.Claim(ctx, 5); err != nil || len(jobs) != 0 {
+		t.Fatalf("Claim after the timeout = %v %v", jobs, err)
+	}
+	if err := q.Complete(ctx, &job); err != queue.ErrLostClaim {
+		t.Fatalf("Complete of a dead job = %v", err)
+	}
+
+	dead, err := q.Dead(ctx, 10)
+	if err != nil || len(dead) != 1 || dead[0].LastError != "visibility timeout expired" {
+		t.Fatalf("Dead = %v %v", dead, err)
+	}
+	if err := q.Retry(ctx, dead[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Retry(ctx, dead[0].ID); err != jorm.ErrNotFound {
+		t.Fatalf("Retry of a pending job = %v", err)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	ctx := context.Background()
+	_, q := open(t, queue.Options{VisibilityTimeout: 100 * time.Millisecond, Backoff: func(int) time.Duration { return 0 }})
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(ctx, &queue.Job{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := 0
+	n, err := q.Process(ctx, 5, func(ctx context.Context, job *queue.Job) error {
+		calls++
+		// outlive the visibility timeout, the heartbeats keep the claim
+		time.Sleep(150 * time.Millisecond)
+		if calls == 2 {
+			return errBoom
+		}
+		return nil
+	})
+	if err != nil || n != 2 || calls != 2 {
+		t.Fatalf("Process = %d %v, %d calls", n, err, calls)
+	}
+
+	jobs, err := q.Claim(ctx, 5)
+	if err != nil || len(jobs) != 1 || jobs[0].LastError != "boom" {
+		t.Fatalf("Claim of the failed job = %v %v", jobs, err)
+	}
+}
+
+func TestProcessLostClaim(t *testing.T) {
+	ctx := context.Background()
+	db, q := open(t, queue.Options{VisibilityTimeout: 100 * time.Millisecond})
+	if err := q.Enqueue(ctx, &queue.Job{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var cancelled bool
+	n, err := q.Process(ctx, 1, func(ctx context.Context, job *queue.Job) error {
+		// another worker claims the job
+		err := db.Model(&queue.Job{}).Where("id = ?", job.ID).Update("attempts", job.Attempts+1).Error()
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			cancelled = true
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	})
+	if n != 1 || err != queue.ErrLostClaim || !cancelled {
+		t.Fatalf("Process = %d %v, cancelled %v", n, err, cancelled)
+	}
+
+	var job queue.Job
+	if err := db.First(&job).Error(); err != nil || job.Status != queue.StatusRunning || job.LastError != "" {
+		t.Fatalf("the job of the lost claim = %+v %v", job, err)
+	}
+}
+
+func TestProcessBatchLostClaim(t *testing.T) {
+	ctx := context.Background()
+	_, q := open(t, queue.Options{VisibilityTimeout: 200 * time.Millisecond})
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(ctx, &queue.Job{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		mu   sync.Mutex
+		runs = map[uint]int{}
+	)
+	handler := func(ctx context.Context, job *queue.Job) error {
+		mu.Lock()
+		runs[job.ID]++
+		mu.Unlock()
+		time.Sleep(300 * time.Millisecond)
+		return nil
+	}
+
+	// the claim of the second job expires while the first runs and another worker claims it
+	var (
+		wg   sync.WaitGroup
+		n    int
+		bErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(250 * time.Millisecond)
+		n, bErr = q.Process(ctx, 2, handler)
+	}()
+	claimed, err := q.Process(ctx, 2, handler)
+	wg.Wait()
+
+	if claimed != 2 || err != queue.ErrLostClaim {
+		t.Errorf("Process of worker A = %d %v", claimed, err)
+	}
+	if n != 1 || bErr != nil {
+		t.Errorf("Process of worker B = %d %v", n, bErr)
+	}
+	if runs[1] != 1 || runs[2] != 1 {
+		t.Errorf("runs = %v", runs)
+	}
+}
+
+func TestFailWithoutCause(t *testing.T) {
+	ctx := context.Background()
+	_, q := open(t, queue.Options{})
+	if err := q.Enqueue(ctx, &queue.Job{}); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := q.Claim(ctx, 1)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("Claim = %v %v", jobs, err)
+	}
+	if err := q.Fail(ctx, &jobs[0], nil); err != nil || jobs[0].Status != queue.StatusPending || jobs[0].LastError != "" {
+		t.Errorf("Fail without a cause = %+v %v", jobs[0], err)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	tests := map[int]time.Duration{0: time.Second, 1: time.Second, 3: 4 * time.Second, 12: 2048 * time.Second, 13: time.Hour, 100: time.Hour}
+	for attempts, want := range tests {
+		if got := queue.ExponentialBackoff(attempts); got != want {
+			t.Errorf("ExponentialBackoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package jorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// readYourWritesHookName is the name the statement hook tracking writes is added under
+const readYourWritesHookName = "jorm:read_your_writes"
+
+// LagProbe returns how far a replica is behind the primary
+type LagProbe func(ctx context.Context, replica *sql.DB) (time.Duration, error)
+
+// ReplicaStatusLag is a LagProbe that reads Seconds_Behind_Source from SHOW REPLICA STATUS, or Seconds_Behind_Master
+// from SHOW SLAVE STATUS before MySQL 8.0.22. A replica whose replication is not running returns an error
+func ReplicaStatusLag(ctx context.Context, replica *sql.DB) (time.Duration, error) {
+	rows, err := replica.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		rows, err = replica.QueryContext(ctx, "SHOW SLAVE STATUS")
+		if err != nil {
+			return 0, err
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("jorm: the database is not a replica")
+	}
+	values := make([]sql.RawBytes, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return 0, err
+	}
+
+	for i, column := range columns {
+		if column != "Seconds_Behind_Source" && column != "Seconds_Behind_Master" {
+			continue
+		}
+		if values[i] == nil {
+			return 0, errors.New("jorm: replication is not running")
+		}
+		seconds, err := strconv.ParseFloat(string(values[i]), 64)
+		return time.Duration(seconds * float64(time.Second)), err
+	}
+	return 0, errors.New("jorm: the replica status has no seconds behind column")
+}
+
+// HeartbeatLag returns a LagProbe that runs a query returning the lag in seconds, typically computed from a heartbeat
+// table the primary updates
+//     jorm.HeartbeatLag("SELECT TIMESTAMPDIFF(MICROSECOND, MAX(ts), UTC_TIMESTAMP(6)) / 1e6 FROM heartbeat")
+func HeartbeatLag(query string) LagProbe {
+	return func(ctx context.Context, replica *sql.DB) (time.Duration, error) {
+		var seconds float64
+		if err := replica.QueryRowContext(ctx, query).Scan(&seconds); err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+}
+
+// LagOptions configures the replica lag monitor
+type LagOptions struct {
+	// MaxLag is how far behind the primary a replica can be before it is taken out of rotation
+	MaxLag time.Duration
+	// Interval is how often the lag of the replicas is checked, defaults to a second, a check times out after it
+	Interval time.Duration
+	// Probe measures the lag of a replica, defaults to ReplicaStatusLag
+	Probe LagProbe
+}
+
+// MonitorReplicaLag returns a clone of the db that checks the lag of its replicas until it is closed, replicas
+// that are behind by more than MaxLag or whose lag cannot be measured are out of rotation until they catch up and
+// reads run on the primary when every replica is out of rotation. The replicas are checked once before it returns
+//     db = db.MonitorReplicaLag(jorm.LagOptions{MaxLag: 2 * time.Second})
+//     defer db.Close()
+func (db *DB) MonitorReplicaLag(opts LagOptions) *DB {
+	c := db.clone(db.db)
+	if db.cluster == nil {
+		return c
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Probe == nil {
+		opts.Probe = ReplicaStatusLag
+	}
+
+	cl := *db.cluster
+	cl.monitor = newLagMonitor(cl.replicas, opts)
+	c.cluster = &cl
+	return c
+}
+
+// lagMonitor keeps replicas that are too far behind out of rotation
+type lagMonitor struct {
+	opts     LagOptions
+	replicas []*sql.DB
+	// behind is 1 for a replica out of rotation, it is accessed atomically
+	behind   []int32
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newLagMonitor checks the replicas and starts checking them every interval
+func newLagMonitor(replicas []*sql.DB, opts LagOptions) *lagMonitor {
+	m := &lagMonitor{opts: opts, replicas: replicas, behind: make([]int32, len(replicas)), done: make(chan struct{})}
+	m.check()
+	go m.run()
+	return m
+}
+
+// run checks the replicas every interval until the monitor is stopped
+func (m *lagMonitor) run() {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check measures the lag of every replica at once and updates which are in rotation
+func (m *lagMonitor) check() {
+	var wg sync.WaitGroup
+	for i, replica := range m.replicas {
+		wg.Add(1)
+		go func(i int, replica *sql.DB) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), m.opts.Interval)
+			defer cancel()
+			var behind int32
+			if lag, err := m.opts.Probe(ctx, replica); err != nil || lag > m.opts.MaxLag {
+				behind = 1
+			}
+			atomic.StoreInt32(&m.behind[i], behind)
+		}(i, replica)
+	}
+	wg.Wait()
+}
+
+// inRotation returns the replicas that are not too far behind
+func (m *lagMonitor) inRotation() []*sql.DB {
+	replicas := make([]*sql.DB, 0, len(m.replicas))
+	for i, replica := range m.replicas {
+		if atomic.LoadInt32(&m.behind[i]) == 0 {
+			replicas = append(replicas, replica)
+		}
+	}
+	return replicas
+}
+
+// stop stops checking the replicas
+func (m *lagMonitor) stop() {
+	m.stopOnce.Do(func() {
+		close(m.done)
+	})
+}
+
+// ReadYourWritesOptions configures how the reads of a context made with TrackWrites see its writes
+type ReadYourWritesOptions struct {
+	// Window is how long after a write of a context its reads run on the primary
+	Window time.Duration
+	// GTID makes the reads of a context run on a replica only once it has executed the GTIDs the primary had
+	// executed after the last write of the context, or on the primary if none has, once Window has passed
+	GTID bool
+}
+
+// writesKey is the context key of the writes tracked by TrackWrites
+type writesKey struct{}
+
+// writes are the last write of a context and the replicas that have caught up with it
+type writes struct {
+	mu   sync.Mutex
+	last time.Time
+	// seq counts the writes visible to other connections, so that what is learned about one is not recorded for the
+	// next
+	seq uint64
+	// gtid is the gtid_executed of the primary after the last write, read by the first read that needs it, and
+	// gtidRead whether it was read
+	gtid     string
+	gtidRead bool
+	// caughtUp are the replicas that have executed gtid, replaced rather than modified
+	caughtUp map[*sql.DB]bool
+}
+
+// TrackWrites returns a context that keeps track of the writes made with it by dbs with WithReadYourWrites, so that
+// the reads made with it see them
+//     ctx = jorm.TrackWrites(ctx)
+//     db.WithContext(ctx).Create(&user)
+//     db.WithContext(ctx).First(&user, user.ID) // runs on the primary or on a replica that has the user
+func TrackWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writesKey{}, &writes{})
+}
+
+// writesOf returns the writes tracked by the context, nil if it does not track them
+func writesOf(ctx context.Context) *writes {
+	if ctx == nil {
+		return nil
+	}
+	w, _ := ctx.Value(writesKey{}).(*writes)
+	return w
+}
+
+// WithReadYourWrites returns a clone of the db whose reads made with a context from TrackWrites see the writes made
+// with it, by running on the primary for a window after a write and then on replicas that have caught up
+//     db = db.WithReadYourWrites(jorm.ReadYourWritesOptions{Window: time.Second, GTID: true})
+func (db *DB) WithReadYourWrites(opts ReadYourWritesOptions) *DB {
+	c := db.clone(db.db)
+	if db.cluster == nil {
+		return c
+	}
+
+	cl := *db.cluster
+	cl.consistency = &opts
+	c.cluster = &cl
+	return c.OnStatement(readYourWritesHookName, cl.trackWrite)
+}
+
+// savepointRegexp matches the savepoint statements of nested transactions, which write nothing
+var savepointRegexp = regexp.MustCompile(`^(SAVEPOINT|RELEASE SAVEPOINT|ROLLBACK TO SAVEPOINT) jorm_savepoint_\d+$`)
+
+// trackWrite records a write in the writes of its context, the gtid_executed of the primary is only read by the
+// first read that needs it so that writes do not wait for another round trip
+func (c *cluster) trackWrite(stmt *Statement) {
+	w := writesOf(stmt.Context)
+	if w == nil || stmt.Error != nil {
+		return
+	}
+	if dryRun, _ := stmt.Get(dryRunSetting); dryRun == true {
+		return
+	}
+	switch stmt.Operation {
+	case OperationCreate, OperationUpdate, OperationDelete, OperationCommit:
+	case OperationExec:
+		if savepointRegexp.MatchString(stmt.SQL) {
+			return
+		}
+	case OperationRaw:
+		if selectRegexp.MatchString(stmt.SQL) {
+			return
+		}
+	default:
+		return
+	}
+
+	// the writes of a transaction are visible to others once it commits
+	inTx := false
+	if stmt.Operation != OperationCommit && stmt.db != nil {
+		_, inTx = unwrapConn(stmt.db.CommonDB()).(sqlTx)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.last = time.Now()
+	if !inTx {
+		w.seq++
+		w.gtid, w.gtidRead = "", false
+		w.caughtUp = nil
+	}
+}
+
+// caughtUpReplica returns a replica that has the writes of a context, nil if the read runs on the primary. The
+// queries to the primary and replicas run without holding the lock of the writes so that the other reads of the
+// context do not wait for them
+func (c *cluster) caughtUpReplica(ctx context.Context, w *writes, replicas []*sql.DB) *sql.DB {
+	w.mu.Lock()
+	last, seq, gtid, gtidRead, caughtUp := w.last, w.seq, w.gtid, w.gtidRead, w.caughtUp
+	w.mu.Unlock()
+
+	if last.IsZero() {
+		return c.policy(replicas)
+	}
+	if time.Since(last) < c.consistency.Window {
+		return nil
+	}
+	if !c.consistency.GTID {
+		return c.policy(replicas)
+	}
+
+	if !gtidRead {
+		// reading it after the write rather than with it may include later writes of others, which only makes the
+		// read wait for more
+		if err := c.primary.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_executed").Scan(&gtid); err != nil {
+			gtid = ""
+		}
+		w.mu.Lock()
+		if w.seq == seq {
+			w.gtid, w.gtidRead = gtid, true
+		}
+		w.mu.Unlock()
+	}
+	if gtid == "" {
+		return nil
+	}
+
+	// try the replica the policy chooses first and then the others in order
+	first := c.policy(replicas)
+	candidates := append([]*sql.DB{first}, replicas...)
+	for i, replica := range candidates {
+		if i > 0 && replica == first {
+			continue
+		}
+		if caughtUp[replica] {
+			return replica
+		}
+		var subset bool
+		err := replica.QueryRowContext(ctx, "SELECT GTID_SUBSET(?, @@GLOBAL.gtid_executed)", gtid).Scan(&subset)
+		if err == nil && subset {
+			w.recordCaughtUp(seq, replica)
+			return replica
+		}
+	}
+	return nil
+}
+
+// recordCaughtUp records that the replica has executed the gtid of the writes, unless another write came since
+func (w *writes) recordCaughtUp(seq uint64, replica *sql.DB) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.seq != seq {
+		return
+	}
+	caughtUp := make(map[*sql.DB]bool, len(w.caughtUp)+1)
+	for r := range w.caughtUp {
+		caughtUp[r] = true
+	}
+	caughtUp[replica] = true
+	w.caughtUp = caughtUp
+}
@@ -0,0 +1,160 @@
+package jorm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jloom6/jorm"
+)
+
+// nameOf returns the name of the only user of a database of openCluster
+func nameOf(ctx context.Context, conn *sql.DB) (string, error) {
+	var name string
+	err := conn.QueryRowContext(ctx, "SELECT name FROM users ORDER BY id LIMIT 1").Scan(&name)
+	return name, err
+}
+
+// probeBehind returns a LagProbe under which the replicas named by behind are an hour behind and the others are not
+func probeBehind(behind func(name string) bool) jorm.LagProbe {
+	return func(ctx context.Context, replica *sql.DB) (time.Duration, error) {
+		name, err := nameOf(ctx, replica)
+		if err != nil {
+			return 0, err
+		}
+		if behind(name) {
+			return time.Hour, nil
+		}
+		return 0, nil
+	}
+}
+
+func TestMonitorReplicaLag(t *testing.T) {
+	var replica1Behind int32 = 1
+	db := openCluster(t).MonitorReplicaLag(jorm.LagOptions{
+		MaxLag:   time.Second,
+		Interval: 5 * time.Millisecond,
+		Probe: probeBehind(func(name string) bool {
+			return name == "replica1" && atomic.LoadInt32(&replica1Behind) == 1
+		}),
+	})
+	defer db.Close()
+
+	// the replicas are checked before MonitorReplicaLag returns
+	for i := 0; i < 3; i++ {
+		if name := firstName(t, db); name != "replica2" {
+			t.Fatalf("read ran on %s, which is behind", name)
+		}
+	}
+
+	atomic.StoreInt32(&replica1Behind, 0)
+	deadline := time.Now().Add(5 * time.Second)
+	for firstName(t, db) != "replica1" {
+		if time.Now().After(deadline) {
+			t.Fatal("replica1 never came back in rotation")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMonitorReplicaLagEveryReplicaBehind(t *testing.T) {
+	db := openCluster(t).MonitorReplicaLag(jorm.LagOptions{
+		MaxLag: time.Second,
+		Probe: func(context.Context, *sql.DB) (time.Duration, error) {
+			return 0, errors.New("replication is not running")
+		},
+	})
+	defer db.Close()
+
+	if name := firstName(t, db); name != "primary" {
+		t.Errorf("read ran on %s", name)
+	}
+}
+
+func TestLagProbes(t *testing.T) {
+	conn := open(t).DB()
+	ctx := context.Background()
+
+	if lag, err := jorm.HeartbeatLag("SELECT 1.5")(ctx, conn); err != nil || lag != 1500*time.Millisecond {
+		t.Errorf("HeartbeatLag = %v %v", lag, err)
+	}
+	// SQLite has no replica status
+	if _, err := jorm.ReplicaStatusLag(ctx, conn); err == nil {
+		t.Error("ReplicaStatusLag of SQLite has no error")
+	}
+}
+
+func TestReadYourWrites(t *testing.T) {
+	cluster := openCluster(t).WithReplicaPolicy(func(replicas []*sql.DB) *sql.DB { return replicas[0] })
+	db := cluster.WithReadYourWrites(jorm.ReadYourWritesOptions{Window: time.Hour})
+
+	ctx := jorm.TrackWrites(context.Background())
+	if name := firstName(t, db.WithContext(ctx)); name != "replica1" {
+		t.Errorf("read before a write ran on %s", name)
+	}
+	// neither a SELECT nor a dry run is a write
+	if err := db.WithContext(ctx).Raw("SELECT name FROM users").Scan(&user{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DryRun().WithContext(ctx).Create(&user{Name: "dry"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if name := firstName(t, db.WithContext(ctx)); name != "replica1" {
+		t.Errorf("read after a SELECT ran on %s", name)
+	}
+
+	if err := db.WithContext(ctx).Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if name := firstName(t, db.WithContext(ctx)); name != "primary" {
+		t.Errorf("read within the window of a write ran on %s", name)
+	}
+	if name := firstName(t, db.WithContext(context.Background())); name != "replica1" {
+		t.Errorf("read of a context without writes ran on %s", name)
+	}
+	if name := firstName(t, db.WithContext(jorm.TrackWrites(context.Background()))); name != "replica1" {
+		t.Errorf("read of another context ran on %s", name)
+	}
+
+	// once the window has passed reads are back on the replicas
+	db = cluster.WithReadYourWrites(jorm.ReadYourWritesOptions{})
+	if err := db.WithContext(ctx).Create(&user{Name: "bob"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if name := firstName(t, db.WithContext(ctx)); name != "replica1" {
+		t.Errorf("read after the window ran on %s", name)
+	}
+}
+
+func TestReadYourWritesOfTransaction(t *testing.T) {
+	db := openCluster(t).WithReadYourWrites(jorm.ReadYourWritesOptions{Window: time.Hour})
+	ctx := jorm.TrackWrites(context.Background())
+
+	err := db.WithContext(ctx).Transaction(ctx, func(tx jorm.Interface) error {
+		return tx.Create(&user{Name: "alice"}).Error()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := firstName(t, db.WithContext(ctx)); name != "primary" {
+		t.Errorf("read after a commit ran on %s", name)
+	}
+}
+
+func TestReadYourWritesGTIDWithoutGTIDs(t *testing.T) {
+	db := openCluster(t).WithReadYourWrites(jorm.ReadYourWritesOptions{GTID: true})
+	ctx := jorm.TrackWrites(context.Background())
+
+	if err := db.WithContext(ctx).Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	// the gtid_executed of SQLite cannot be read, so no replica is known to have the write
+	for i := 0; i < 2; i++ {
+		if name := firstName(t, db.WithContext(ctx)); name != "primary" {
+			t.Errorf("read ran on %s", name)
+		}
+	}
+}
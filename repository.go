@@ -0,0 +1,102 @@
+package jorm
+
+import (
+	"context"
+)
+
+// ListOptions filter, order and page the records returned by Repository.List and counted by Repository.Count
+type ListOptions struct {
+	// Where and Args are passed to Where if Where is not nil
+	Where interface{}
+	Args  []interface{}
+	// Order is passed to Order if it is not empty
+	Order string
+	// Limit and Offset are only applied if they are greater than 0, Count ignores them
+	Limit  int
+	Offset int
+}
+
+// Repository contains the common operations on the records of a model, returning values rather than an Interface
+type Repository[T any] interface {
+	Get(ctx context.Context, id interface{}) (T, error)
+	List(ctx context.Context, opts ListOptions) ([]T, error)
+	Create(ctx context.Context, value *T) error
+	Update(ctx context.Context, value *T) error
+	Delete(ctx context.Context, value *T) error
+	Count(ctx context.Context, opts ListOptions) (int64, error)
+	Exists(ctx context.Context, id interface{}) (bool, error)
+}
+
+// repository implements Repository on top of an Interface
+type repository[T any] struct {
+	db Interface
+}
+
+// NewRepository returns a Repository for model T which runs its queries through the given db
+//     users := jorm.NewRepository[User](db)
+//     user, err := users.Get(ctx, 1)
+func NewRepository[T any](db Interface) Repository[T] {
+	return &repository[T]{db: db}
+}
+
+// Get find the record with the given primary key, returns gorm.ErrRecordNotFound if there is none
+func (r *repository[T]) Get(ctx context.Context, id interface{}) (T, error) {
+	var value T
+	err := r.db.WithContext(ctx).First(&value, id).Error()
+	return value, err
+}
+
+// List find the records that match the given options
+func (r *repository[T]) List(ctx context.Context, opts ListOptions) ([]T, error) {
+	db := r.filter(r.db.WithContext(ctx), opts)
+	if opts.Limit > 0 {
+		db = db.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		db = db.Offset(opts.Offset)
+	}
+
+	var values []T
+	err := db.Find(&values).Error()
+	return values, err
+}
+
+// Create insert the value into database
+func (r *repository[T]) Create(ctx context.Context, value *T) error {
+	return r.db.WithContext(ctx).Create(value).Error()
+}
+
+// Update save all fields of the value, inserting it if it doesn't have a primary key
+func (r *repository[T]) Update(ctx context.Context, value *T) error {
+	return r.db.WithContext(ctx).Save(value).Error()
+}
+
+// Delete delete the value by its primary key
+func (r *repository[T]) Delete(ctx context.Context, value *T) error {
+	return r.db.WithContext(ctx).Delete(value).Error()
+}
+
+// Count get how many records match the given options
+func (r *repository[T]) Count(ctx context.Context, opts ListOptions) (int64, error) {
+	var count int64
+	err := r.filter(r.db.WithContext(ctx).Model(new(T)), opts).Count(&count).Error()
+	return count, err
+}
+
+// Exists check if there is a record with the given primary key, counting it rather than loading it
+func (r *repository[T]) Exists(ctx context.Context, id interface{}) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(new(T)).Where(id).Count(&count).Error()
+	return count > 0, err
+}
+
+// filter applies the conditions and order of the options to the db
+func (r *repository[T]) filter(db Interface, opts ListOptions) Interface {
+	if opts.Where != nil {
+		db = db.Where(opts.Where, opts.Args...)
+	}
+	if opts.Order != "" {
+		db = db.Order(opts.Order)
+	}
+	return db
+}
@@ -0,0 +1,79 @@
+package jorm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jloom6/jorm"
+)
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	users := jorm.NewRepository[user](open(t))
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		if err := users.Create(ctx, &user{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bob, err := users.Get(ctx, 2)
+	if err != nil || bob.Name != "bob" {
+		t.Fatalf("Get(2) = %+v %v", bob, err)
+	}
+	if _, err := users.Get(ctx, 99); !errors.Is(err, jorm.ErrNotFound) {
+		t.Errorf("Get(99) = %v", err)
+	}
+
+	bob.Name = "robert"
+	if err := users.Update(ctx, &bob); err != nil {
+		t.Fatal(err)
+	}
+	list, err := users.List(ctx, jorm.ListOptions{Order: "name DESC", Limit: 2})
+	if err != nil || len(list) != 2 || list[0].Name != "robert" || list[1].Name != "carol" {
+		t.Errorf("List = %v %v", list, err)
+	}
+	list, err = users.List(ctx, jorm.ListOptions{Where: "name <> ?", Args: []interface{}{"alice"}, Order: "id", Limit: 5, Offset: 1})
+	if err != nil || len(list) != 1 || list[0].Name != "carol" {
+		t.Errorf("List with Where and Offset = %v %v", list, err)
+	}
+
+	count, err := users.Count(ctx, jorm.ListOptions{Where: "name LIKE ?", Args: []interface{}{"%r%"}, Limit: 1})
+	if err != nil || count != 2 {
+		t.Errorf("Count = %d %v", count, err)
+	}
+
+	if err := users.Delete(ctx, &bob); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := users.Count(ctx, jorm.ListOptions{}); err != nil || count != 2 {
+		t.Errorf("Count after Delete = %d %v", count, err)
+	}
+}
+
+func TestRepositoryExistsCounts(t *testing.T) {
+	ctx := context.Background()
+	var queries []string
+	db := open(t).OnStatement("queries", func(stmt *jorm.Statement) {
+		if stmt.Operation != jorm.OperationCreate {
+			queries = append(queries, stmt.SQL)
+		}
+	})
+	users := jorm.NewRepository[user](db)
+	if err := users.Create(ctx, &user{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[uint]bool{1: true, 2: false} {
+		queries = nil
+		if got, err := users.Exists(ctx, id); err != nil || got != want {
+			t.Errorf("Exists(%d) = %v %v, want %v", id, got, err, want)
+		}
+		if len(queries) != 1 || !strings.HasPrefix(strings.ToLower(queries[0]), "select count(*)") {
+			t.Errorf("Exists(%d) ran %s, want a count", id, fmt.Sprint(queries))
+		}
+	}
+}
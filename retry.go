@@ -0,0 +1,92 @@
+package jorm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// RetryPolicy configures how RetryTransaction retries a transaction
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the transaction is run, including the first attempt
+	MaxAttempts int
+	// InitialBackoff is how long to wait before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps how long to wait between retries
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every retry
+	Multiplier float64
+	// Jitter is the fraction of the backoff that is randomized, between 0 and 1
+	Jitter float64
+}
+
+// DefaultRetryPolicy is a sensible policy for retrying deadlocked transactions
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// IsRetryable reports whether err is classified as ErrDeadlock or ErrLockTimeout
+func IsRetryable(err error) bool {
+	err = classifyAny(err)
+	return errors.Is(err, ErrDeadlock) || errors.Is(err, ErrLockTimeout)
+}
+
+// RetryTransaction runs fn in a transaction with db.Transaction, running it again with backoff if it fails
+// with an error for which IsRetryable is true.  Any other error is returned immediately.
+// The number of attempts is tagged on the span of the context, if there is one.
+// When db is already in a transaction fn is never retried, as MySQL rolls back the whole transaction on a deadlock
+//     err := jorm.RetryTransaction(ctx, db, jorm.DefaultRetryPolicy, func(tx jorm.Interface) error {
+//         return tx.Model(&account).Update("balance", gorm.Expr("balance - ?", amount)).Error()
+//     })
+func RetryTransaction(ctx context.Context, db Interface, policy RetryPolicy, fn func(tx Interface) error) error {
+	span := opentracing.SpanFromContext(ctx)
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := db.Transaction(ctx, fn)
+		if span != nil {
+			span.SetTag("db.transaction.attempts", attempt)
+		}
+		if err == nil || !IsRetryable(err) || attempt >= policy.MaxAttempts || db.TxDepth() > 0 {
+			return err
+		}
+		if span != nil {
+			span.LogKV("event", "retry", "attempt", attempt, "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(policy.jitter(backoff)):
+		}
+
+		backoff = policy.next(backoff)
+	}
+}
+
+// jitter randomizes the given backoff by up to the policy's Jitter fraction in either direction
+func (p RetryPolicy) jitter(backoff time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return backoff
+	}
+	delta := p.Jitter * float64(backoff)
+	return time.Duration(float64(backoff) - delta + rand.Float64()*2*delta)
+}
+
+// next returns the backoff to use after the given one
+func (p RetryPolicy) next(backoff time.Duration) time.Duration {
+	if p.Multiplier > 0 {
+		backoff = time.Duration(float64(backoff) * p.Multiplier)
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,101 @@
+package jorm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jloom6/jorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+// retryPolicy retries without waiting
+var retryPolicy = jorm.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+func TestRetryTransactionRetriesDeadlocks(t *testing.T) {
+	db := open(t)
+	span := mocktracer.New().StartSpan("test").(*mocktracer.MockSpan)
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+	attempts := 0
+	err := jorm.RetryTransaction(ctx, db, retryPolicy, func(tx jorm.Interface) error {
+		attempts++
+		if err := tx.Create(&user{Name: fmt.Sprint("attempt ", attempts)}).Error(); err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("RetryTransaction = %v after %d attempts", err, attempts)
+	}
+	if got := fmt.Sprint(names(t, db)); got != "[attempt 3]" {
+		t.Errorf("users = %s, the failed attempts must be rolled back", got)
+	}
+	if got := span.Tag("db.transaction.attempts"); got != 3 {
+		t.Errorf("db.transaction.attempts = %v", got)
+	}
+}
+
+func TestRetryTransactionGivesUp(t *testing.T) {
+	db := open(t)
+
+	attempts := 0
+	err := jorm.RetryTransaction(context.Background(), db, retryPolicy, func(tx jorm.Interface) error {
+		attempts++
+		return &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+	})
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != 1205 || attempts != 3 {
+		t.Errorf("RetryTransaction = %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = jorm.RetryTransaction(context.Background(), db, retryPolicy, func(tx jorm.Interface) error {
+		attempts++
+		return errBoom
+	})
+	if err != errBoom || attempts != 1 {
+		t.Errorf("RetryTransaction of an error that is not retryable = %v after %d attempts", err, attempts)
+	}
+}
+
+func TestRetryTransactionInTransaction(t *testing.T) {
+	db := open(t)
+
+	attempts := 0
+	err := db.Transaction(context.Background(), func(tx jorm.Interface) error {
+		return jorm.RetryTransaction(context.Background(), tx, retryPolicy, func(tx jorm.Interface) error {
+			attempts++
+			return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		})
+	})
+	if !jorm.IsRetryable(err) || attempts != 1 {
+		t.Errorf("RetryTransaction in a transaction = %v after %d attempts", err, attempts)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&mysql.MySQLError{Number: 1213}, true},
+		{&mysql.MySQLError{Number: 1205}, true},
+		{fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1213}), true},
+		{&mysql.MySQLError{Number: 1062}, false},
+		{errBoom, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := jorm.IsRetryable(test.err); got != test.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package jorm
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Each scans every row of rows into result with db.ScanRows and calls fn after each one, so large results can be
+// processed one record at a time, it stops at the first error and always closes rows
+//     rows, err := db.Model(&User{}).Where("active = ?", true).Rows()
+//     if err != nil {
+//         return err
+//     }
+//     var user User
+//     err = jorm.Each(db, rows, &user, func() error {
+//         return notify(user)
+//     })
+func Each(db Interface, rows Rows, result interface{}, fn func() error) (err error) {
+	// Rows returns a nil *sql.Rows along with its error
+	if sqlRows, ok := rows.(*sql.Rows); rows == nil || ok && sqlRows == nil {
+		return errors.New("jorm: Each requires non nil rows")
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	value := reflect.ValueOf(result)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return errors.New("jorm: Each requires a non nil pointer result")
+	}
+
+	for rows.Next() {
+		// fields of NULL or unselected columns are not scanned so they must not keep the previous row's values
+		value.Elem().Set(reflect.Zero(value.Elem().Type()))
+		if err := db.ScanRows(rows, result); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanRows scans the current row into the fields of the scope's value the same way gorm does for `*sql.Rows`
+func scanRows(scope *gorm.Scope, rows Rows) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	var (
+		ignored            interface{}
+		fields             = scope.Fields()
+		values             = make([]interface{}, len(columns))
+		selectedColumnsMap = map[string]int{}
+		resetFields        = map[int]*gorm.Field{}
+	)
+
+	for index, column := range columns {
+		values[index] = &ignored
+
+		selectFields := fields
+		offset := 0
+		if idx, ok := selectedColumnsMap[column]; ok {
+			offset = idx + 1
+			selectFields = selectFields[offset:]
+		}
+
+		for fieldIndex, field := range selectFields {
+			if field.DBName != column {
+				continue
+			}
+
+			if field.Field.Kind() == reflect.Ptr {
+				values[index] = field.Field.Addr().Interface()
+			} else {
+				reflectValue := reflect.New(reflect.PtrTo(field.Struct.Type))
+				reflectValue.Elem().Set(field.Field.Addr())
+				values[index] = reflectValue.Interface()
+				resetFields[index] = field
+			}
+
+			selectedColumnsMap[column] = offset + fieldIndex
+			if field.IsNormal {
+				break
+			}
+		}
+	}
+
+	if err := rows.Scan(values...); err != nil {
+		return err
+	}
+
+	for index, field := range resetFields {
+		if v := reflect.ValueOf(values[index]).Elem().Elem(); v.IsValid() {
+			field.Field.Set(v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package jorm_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/jloom6/jorm"
+	"github.com/jloom6/jorm/jormtest"
+)
+
+type profile struct {
+	ID   uint
+	Name string
+	Nick *string
+	Note sql.NullString
+}
+
+func TestEachWithRows(t *testing.T) {
+	db := open(t)
+	rows := jormtest.NewRows([]string{"id", "name", "nick", "note", "extra"},
+		[]interface{}{int64(1), []byte("bob"), "b", "note", 5},
+		[]interface{}{2, "alice", nil, nil, nil},
+	)
+
+	var (
+		p   profile
+		got []profile
+	)
+	err := jorm.Each(db, rows, &p, func() error {
+		got = append(got, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("scanned %+v", got)
+	}
+	if got[0].ID != 1 || got[0].Name != "bob" || got[0].Nick == nil || *got[0].Nick != "b" || got[0].Note.String != "note" {
+		t.Errorf("first row = %+v", got[0])
+	}
+	// NULL columns do not keep the values of the previous row
+	if got[1].ID != 2 || got[1].Name != "alice" || got[1].Nick != nil || got[1].Note.Valid {
+		t.Errorf("second row = %+v", got[1])
+	}
+	if !rows.Closed() {
+		t.Error("Each did not close the rows")
+	}
+}
+
+func TestEachErrors(t *testing.T) {
+	db := open(t)
+	var p profile
+
+	rows := jormtest.NewRows([]string{"id"}, []interface{}{1}).WithError(errBoom)
+	if err := jorm.Each(db, rows, &p, func() error { return nil }); err != errBoom {
+		t.Errorf("Each of rows failing = %v", err)
+	}
+
+	rows = jormtest.NewRows([]string{"id"}, []interface{}{1}, []interface{}{2})
+	calls := 0
+	err := jorm.Each(db, rows, &p, func() error {
+		calls++
+		return errBoom
+	})
+	if err != errBoom || calls != 1 || !rows.Closed() {
+		t.Errorf("Each with fn failing = %v after %d calls, closed %v", err, calls, rows.Closed())
+	}
+
+	if err := jorm.Each(db, jormtest.NewRows([]string{"id"}), p, func() error { return nil }); err == nil {
+		t.Error("Each into a non pointer has no error")
+	}
+}
+
+func TestEachOfFailedRows(t *testing.T) {
+	db := open(t)
+	var u user
+
+	rows, err := db.Table("missing").Rows()
+	if err == nil {
+		t.Fatal("Rows of a missing table has no error")
+	}
+	if err := jorm.Each(db, rows, &u, func() error { return nil }); err == nil {
+		t.Error("Each of the rows of a failed query has no error")
+	}
+	if err := jorm.Each(db, nil, &u, func() error { return nil }); err == nil {
+		t.Error("Each of nil rows has no error")
+	}
+}
+
+func TestScanRowsOfDatabase(t *testing.T) {
+	db := open(t)
+	for _, name := range []string{"alice", "bob"} {
+		if err := db.Create(&user{Name: name}).Error(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.Model(&user{}).Order("id").Rows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		u     user
+		names []string
+	)
+	if err := jorm.Each(db, rows, &u, func() error {
+		names = append(names, u.Name)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(names) != "[alice bob]" {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestScanRowsWithFake(t *testing.T) {
+	fake := jormtest.NewFake(t)
+	rows := jormtest.NewRows([]string{"id", "name"}, []interface{}{3, "carol"})
+
+	var p profile
+	if !rows.Next() {
+		t.Fatal("no row")
+	}
+	if err := fake.ScanRows(rows, &p); err != nil || p.ID != 3 || p.Name != "carol" {
+		t.Errorf("ScanRows = %+v %v", p, err)
+	}
+}
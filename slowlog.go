@@ -0,0 +1,168 @@
+package jorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SlowThresholdSetting is the gorm setting that overrides the slow query threshold of a call chain,
+// a duration of zero or less turns the slow query log off
+//     db.Set(jorm.SlowThresholdSetting, time.Duration(0)).Exec(batchUpdate)
+const SlowThresholdSetting = "jorm:slow_threshold"
+
+// slowQueryHookName is the name the statement hook of the slow query log is added under
+const slowQueryHookName = "jorm:slow_query"
+
+// SlowQuery is the log entry of a statement that took longer than the slow query threshold
+type SlowQuery struct {
+	Operation string
+	Table     string
+	SQL       string
+	// Args are the arguments of the statement after redaction
+	Args         []string
+	Duration     time.Duration
+	Threshold    time.Duration
+	RowsAffected int64
+	Error        error
+	// Caller is the file:line of the code outside of jorm and gorm that ran the statement
+	Caller string
+	// TraceID is the ID of the trace of the context given to WithContext
+	TraceID string
+}
+
+// SlowQueryOptions configures the slow query log
+type SlowQueryOptions struct {
+	// Threshold is how long a statement must take to be logged, zero turns the log off unless a call chain sets
+	// SlowThresholdSetting
+	Threshold time.Duration
+	// Redact converts an argument of a statement for the log, defaults to RedactArg
+	Redact func(arg interface{}) string
+	// Log writes an entry, defaults to logging it at LevelWarn with the logger set with WithLogger, or with the
+	// standard logger as key=value pairs if there is none
+	Log func(entry SlowQuery)
+}
+
+// LogSlowQueries returns a clone of the db that logs every statement taking longer than the threshold
+//     db = db.LogSlowQueries(jorm.SlowQueryOptions{Threshold: 200 * time.Millisecond})
+func (db *DB) LogSlowQueries(opts SlowQueryOptions) *DB {
+	if opts.Redact == nil {
+		opts.Redact = RedactArg
+	}
+
+	return db.OnStatement(slowQueryHookName, func(stmt *Statement) {
+		threshold := opts.Threshold
+		if value, ok := stmt.Get(SlowThresholdSetting); ok {
+			if d, ok := value.(time.Duration); ok {
+				threshold = d
+			}
+		}
+		if threshold <= 0 || stmt.Duration < threshold {
+			return
+		}
+
+		args := make([]string, len(stmt.Vars))
+		for i, v := range stmt.Vars {
+			args[i] = opts.Redact(v)
+		}
+		entry := SlowQuery{
+			Operation:    stmt.Operation,
+			Table:        stmt.Table,
+			SQL:          strings.TrimSpace(stmt.SQL),
+			Args:         args,
+			Duration:     stmt.Duration,
+			Threshold:    threshold,
+			RowsAffected: stmt.RowsAffected,
+			Error:        stmt.Error,
+			Caller:       caller(),
+			TraceID:      stmt.TraceID(),
+		}
+		if opts.Log != nil {
+			opts.Log(entry)
+		} else {
+			logSlowQuery(stmt, entry)
+		}
+	})
+}
+
+// RedactArg keeps NULL, booleans, numbers and times, which are rarely sensitive, and hides every other argument
+func RedactArg(arg interface{}) string {
+	v := reflect.ValueOf(indirectArg(arg))
+	switch v.Kind() {
+	case reflect.Invalid:
+		return "NULL"
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface())
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return "<redacted>"
+}
+
+// indirectArg dereferences pointers and driver.Valuers down to the value sent to the database, nil for NULL
+func indirectArg(arg interface{}) interface{} {
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		return value
+	}
+	return v.Interface()
+}
+
+// logSlowQuery writes an entry with the logger of the statement's db or the standard logger
+func logSlowQuery(stmt *Statement, entry SlowQuery) {
+	if logger := loggerOf(stmt.Get); logger != nil {
+		logger.Log(stmt.Context, LevelWarn, "slow query", "operation", entry.Operation, "table", entry.Table,
+			"statement", entry.SQL, "args", entry.Args, "duration", entry.Duration, "threshold", entry.Threshold,
+			"rows", entry.RowsAffected, "error", entry.Error, "caller", entry.Caller, "trace_id", entry.TraceID)
+		return
+	}
+
+	log.Printf("jorm: slow query operation=%s table=%q duration=%v threshold=%v rows_affected=%d caller=%s trace_id=%q sql=%q args=%v error=%v",
+		entry.Operation, entry.Table, entry.Duration, entry.Threshold, entry.RowsAffected, entry.Caller, entry.TraceID,
+		entry.SQL, entry.Args, entry.Error)
+}
+
+// caller returns the file:line of the first caller outside of jorm, gorm and the runtime
+func caller() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !isInternalFrame(frame.Function) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isInternalFrame reports whether the function belongs to jorm, gorm or the runtime
+func isInternalFrame(function string) bool {
+	for _, prefix := range []string{"github.com/jloom6/jorm.", "github.com/jinzhu/gorm.", "runtime.", "reflect."} {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package jorm_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jloom6/jorm"
+)
+
+func TestLogSlowQueries(t *testing.T) {
+	var entries []jorm.SlowQuery
+	db := open(t).LogSlowQueries(jorm.SlowQueryOptions{
+		Threshold: time.Nanosecond,
+		Log:       func(entry jorm.SlowQuery) { entries = append(entries, entry) },
+	})
+
+	if err := db.Where("name = ? AND id > ?", "secret", 3).Find(&[]user{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	// a call chain can turn the log off
+	if err := db.Set(jorm.SlowThresholdSetting, time.Duration(0)).Exec("DELETE FROM users").Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("logged %+v", entries)
+	}
+	entry := entries[0]
+	if entry.Operation != "find" || entry.Table != "users" || !strings.HasPrefix(entry.SQL, "SELECT") {
+		t.Errorf("entry = %+v", entry)
+	}
+	if strings.Join(entry.Args, ",") != "<redacted>,3" {
+		t.Errorf("Args = %v", entry.Args)
+	}
+	if entry.Threshold != time.Nanosecond || entry.Duration < entry.Threshold {
+		t.Errorf("Duration = %v, Threshold = %v", entry.Duration, entry.Threshold)
+	}
+	if !strings.Contains(entry.Caller, "slowlog_test.go:") {
+		t.Errorf("Caller = %q", entry.Caller)
+	}
+}
+
+func TestLogSlowQueriesThreshold(t *testing.T) {
+	var entries []jorm.SlowQuery
+	db := open(t).LogSlowQueries(jorm.SlowQueryOptions{
+		Threshold: time.Hour,
+		Log:       func(entry jorm.SlowQuery) { entries = append(entries, entry) },
+	})
+
+	if err := db.Create(&user{Name: "alice"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("logged a fast statement %+v", entries)
+	}
+	if err := db.Set(jorm.SlowThresholdSetting, time.Nanosecond).Create(&user{Name: "bob"}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Operation != "create" || entries[0].Threshold != time.Nanosecond {
+		t.Errorf("logged %+v", entries)
+	}
+}
+
+func TestRedactArg(t *testing.T) {
+	name := "secret"
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		arg  interface{}
+		want string
+	}{
+		{nil, "NULL"},
+		{(*string)(nil), "NULL"},
+		{true, "true"},
+		{42, "42"},
+		{1.5, "1.5"},
+		{now, "2020-01-02T03:04:05Z"},
+		{"secret", "<redacted>"},
+		{&name, "<redacted>"},
+		{[]byte("secret"), "<redacted>"},
+		{sql.NullInt64{Int64: 4, Valid: true}, "4"},
+		{sql.NullString{}, "NULL"},
+		{sql.NullString{String: "secret", Valid: true}, "<redacted>"},
+	}
+	for _, test := range tests {
+		if got := jorm.RedactArg(test.arg); got != test.want {
+			t.Errorf("RedactArg(%#v) = %q, want %q", test.arg, got, test.want)
+		}
+	}
+}
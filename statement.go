@@ -0,0 +1,245 @@
+package jorm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// gorm settings used to pass statement hooks to the gorm callbacks
+const (
+	hooksSetting = "jorm:statement_hooks"
+	startSetting = "jorm:statement_start"
+	rawSetting   = "jorm:raw"
+)
+
+// Operations a Statement can be
+const (
+	OperationFind     = "find"
+	OperationCreate   = "create"
+	OperationUpdate   = "update"
+	OperationDelete   = "delete"
+	OperationRaw      = "raw"
+	OperationExec     = "exec"
+	OperationBegin    = "begin"
+	OperationCommit   = "commit"
+	OperationRollback = "rollback"
+)
+
+// Statement describes a statement run by a db, it is passed to the hooks added with OnStatement once it is done
+type Statement struct {
+	// Context is the context given to WithContext, context.Background() if there is none
+	Context context.Context
+	// Dialect is the name of the dialect of the db, as returned by Dialect().GetName()
+	Dialect string
+	// Operation is one of the Operation constants
+	Operation string
+	// Table is the table of the model of the statement, empty for Exec and transactions
+	Table string
+	// SQL is the statement with placeholders for Vars
+	SQL string
+	// Vars are the values of the placeholders of SQL
+	Vars []interface{}
+	// StartTime is when the statement started and Duration how long it took
+	StartTime time.Time
+	Duration  time.Duration
+	// RowsAffected is the number of rows found by queries or changed by other statements
+	RowsAffected int64
+	// Error is the classified error of the statement
+	Error error
+	// EndedTransaction reports whether a commit or rollback ended a transaction that began without error, it is
+	// false for those of a transaction that failed to begin or had already ended
+	EndedTransaction bool
+
+	// db is the gorm db that ran the statement
+	db *gorm.DB
+}
+
+// Get returns the value of a setting of the db that ran the statement, set with Set or InstantSet
+func (stmt *Statement) Get(name string) (interface{}, bool) {
+	if stmt.db == nil {
+		return nil, false
+	}
+	return stmt.db.Get(name)
+}
+
+// TraceID returns the ID of the trace of the statement's context, or an empty string if it has none
+func (stmt *Statement) TraceID() string {
+	if stmt.Context == nil {
+		return ""
+	}
+	if stmt.db == nil {
+		return OpenTracing().TraceID(stmt.Context)
+	}
+	return tracerOf(stmt.db).TraceID(stmt.Context)
+}
+
+// StatementHook is called after every statement of a db it was added to
+type StatementHook func(stmt *Statement)
+
+// statementHook is a hook added with OnStatement
+type statementHook struct {
+	name string
+	hook StatementHook
+}
+
+// statementHooks are never changed once they are stored in the settings of a db, so clones can share them
+type statementHooks []statementHook
+
+var registerCallbacksMu sync.Mutex
+
+// OnStatement returns a clone of the db that calls hook after every statement run by it or any db derived from it,
+// a hook added before under the same name is replaced
+//     db = db.OnStatement("log", func(stmt *jorm.Statement) {
+//         log.Printf("%s took %v", stmt.SQL, stmt.Duration)
+//     })
+func (db *DB) OnStatement(name string, hook StatementHook) *DB {
+	registerStatementCallbacks(db.db)
+
+	hooks := statementHooks{}
+	for _, h := range db.statementHooks() {
+		if h.name != name {
+			hooks = append(hooks, h)
+		}
+	}
+	hooks = append(hooks, statementHook{name: name, hook: hook})
+	return db.clone(db.db.Set(hooksSetting, hooks))
+}
+
+// RemoveStatementHook returns a clone of the db without the hook added under the given name
+func (db *DB) RemoveStatementHook(name string) *DB {
+	hooks := statementHooks{}
+	for _, h := range db.statementHooks() {
+		if h.name != name {
+			hooks = append(hooks, h)
+		}
+	}
+	return db.clone(db.db.Set(hooksSetting, hooks))
+}
+
+// statementHooks returns the hooks of the db
+func (db *DB) statementHooks() statementHooks {
+	hooks, _ := db.db.Get(hooksSetting)
+	h, _ := hooks.(statementHooks)
+	return h
+}
+
+// statementDone calls the hooks of the db for a statement run by jorm rather than by gorm callbacks
+func (db *DB) statementDone(operation, sql string, vars []interface{}, start time.Time, result *gorm.DB) {
+	hooks := db.statementHooks()
+	if len(hooks) == 0 {
+		return
+	}
+	hooks.run(newStatement(operation, sql, vars, start, result))
+}
+
+// txStatementDone calls the hooks of the db for the commit or rollback of a transaction, ended reports whether it
+// ended an open transaction
+func (db *DB) txStatementDone(operation, sql string, start time.Time, ended bool) {
+	hooks := db.statementHooks()
+	if len(hooks) == 0 {
+		return
+	}
+	stmt := newStatement(operation, sql, nil, start, db.db)
+	stmt.EndedTransaction = ended
+	hooks.run(stmt)
+}
+
+// newStatement returns the statement run by jorm with the result
+func newStatement(operation, sql string, vars []interface{}, start time.Time, result *gorm.DB) *Statement {
+	return &Statement{
+		Context:      contextOf(result),
+		Dialect:      result.Dialect().GetName(),
+		Operation:    operation,
+		SQL:          sql,
+		Vars:         vars,
+		StartTime:    start,
+		Duration:     time.Since(start),
+		RowsAffected: result.RowsAffected,
+		Error:        Classify(result.Dialect().GetName(), result.Error),
+		db:           result,
+	}
+}
+
+// run calls every hook in the order they were added
+func (hooks statementHooks) run(stmt *Statement) {
+	for _, h := range hooks {
+		h.hook(stmt)
+	}
+}
+
+// contextOf returns the context stored in the settings of the db
+func contextOf(db *gorm.DB) context.Context {
+	if ctx, ok := db.Get(contextSetting); ok {
+		return ctx.(context.Context)
+	}
+	return context.Background()
+}
+
+// registerStatementCallbacks registers the gorm callbacks that call statement hooks,
+// gorm shares callbacks between every db opened together so they are registered once
+func registerStatementCallbacks(db *gorm.DB) {
+	registerCallbacksMu.Lock()
+	defer registerCallbacksMu.Unlock()
+
+	callback := db.Callback()
+	if callback.Query().Get("jorm:after_query") != nil {
+		return
+	}
+
+	callback.Create().Before("gorm:begin_transaction").Register("jorm:before_create", beforeStatement)
+	callback.Create().After("gorm:commit_or_rollback_transaction").Register("jorm:after_create", afterStatement(OperationCreate))
+	callback.Update().Before("gorm:begin_transaction").Register("jorm:before_update", beforeStatement)
+	callback.Update().After("gorm:commit_or_rollback_transaction").Register("jorm:after_update", afterStatement(OperationUpdate))
+	callback.Delete().Before("gorm:begin_transaction").Register("jorm:before_delete", beforeStatement)
+	callback.Delete().After("gorm:commit_or_rollback_transaction").Register("jorm:after_delete", afterStatement(OperationDelete))
+	callback.Query().Before("gorm:query").Register("jorm:before_query", beforeStatement)
+	callback.Query().After("gorm:after_query").Register("jorm:after_query", afterStatement(OperationFind))
+	callback.RowQuery().Before("gorm:row_query").Register("jorm:before_row_query", beforeStatement)
+	callback.RowQuery().After("gorm:row_query").Register("jorm:after_row_query", afterStatement(OperationFind))
+}
+
+// beforeStatement records when a statement with hooks starts
+func beforeStatement(scope *gorm.Scope) {
+	if _, ok := scope.Get(hooksSetting); ok {
+		scope.Set(startSetting, time.Now())
+	}
+}
+
+// afterStatement calls the hooks of a statement run by gorm callbacks
+func afterStatement(operation string) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		hooks, ok := scope.Get(hooksSetting)
+		if !ok || len(hooks.(statementHooks)) == 0 {
+			return
+		}
+
+		start, _ := scope.Get(startSetting)
+		startTime, _ := start.(time.Time)
+		op := operation
+		if raw, _ := scope.Get(rawSetting); raw == true {
+			op = OperationRaw
+		}
+
+		table := ""
+		if scope.Value != nil {
+			table = scope.TableName()
+		}
+
+		hooks.(statementHooks).run(&Statement{
+			Context:      contextOf(scope.DB()),
+			Dialect:      scope.Dialect().GetName(),
+			Operation:    op,
+			Table:        table,
+			SQL:          scope.SQL,
+			Vars:         scope.SQLVars,
+			StartTime:    startTime,
+			Duration:     time.Since(startTime),
+			RowsAffected: scope.DB().RowsAffected,
+			Error:        Classify(scope.Dialect().GetName(), scope.DB().Error),
+			db:           scope.DB(),
+		})
+	}
+}
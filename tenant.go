@@ -0,0 +1,271 @@
+package jorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jinzhu/gorm"
+)
+
+// TenantTag is the tag of the tenant column of a model, the statements of a model with one are restricted to the
+// tenant of their context
+//     type Invoice struct {
+//         ID       uint
+//         TenantID uint `jorm:"tenant"`
+//     }
+const TenantTag = "tenant"
+
+// gorm settings of dbs that are not restricted to a tenant and of unscoped dbs
+const (
+	withoutTenantScopeSetting = "jorm:without_tenant_scope"
+	unscopedSetting           = "jorm:unscoped"
+)
+
+var (
+	// ErrNoTenant is returned by statements on a model with a tenant column whose context has no tenant
+	ErrNoTenant = errors.New("jorm: no tenant")
+	// ErrTenantScopeBypass is returned by Raw and Exec statements whose context has a tenant, by statements on a
+	// table given to Table that is not the table of a model with a tenant column whose context has a tenant, such as
+	// the join tables of many to many associations, and by Unscoped statements on a model with a tenant column,
+	// unless they are made with WithoutTenantScope
+	ErrTenantScopeBypass = errors.New("jorm: statement bypasses the tenant scope")
+	// ErrTenantMismatch is returned when a record is created or saved, or its tenant column updated, with another
+	// tenant than the one of the context
+	ErrTenantMismatch = errors.New("jorm: record of another tenant")
+)
+
+// tenantKey is the context key of the tenant given to WithTenant
+type tenantKey struct{}
+
+// WithTenant returns a context whose statements are restricted to the tenant, queries, updates and deletes of models
+// with a tenant column only match the records of the tenant and creates and saves fill the column with it
+//     ctx = jorm.WithTenant(ctx, tenantID)
+//     db.WithContext(ctx).Find(&invoices) // SELECT * FROM invoices WHERE invoices.tenant_id = ?
+func WithTenant(ctx context.Context, tenant interface{}) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantOf returns the tenant of the context, false if it has none
+func TenantOf(ctx context.Context) (interface{}, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tenant := ctx.Value(tenantKey{})
+	return tenant, tenant != nil
+}
+
+// WithoutTenantScope returns a clone of the db whose statements are not restricted to a tenant, it is required to
+// run Raw, Exec, Table and Unscoped statements with a context that has a tenant and statements on models with a
+// tenant column without one
+func (db *DB) WithoutTenantScope() Interface {
+	return db.clone(db.db.Set(withoutTenantScopeSetting, true))
+}
+
+// tenantScope returns the tenant column of the model of a statement and the tenant of its context, a nil column if
+// the statement is not restricted to a tenant
+func tenantScope(scope *gorm.Scope) (*gorm.StructField, interface{}, error) {
+	if without, _ := scope.Get(withoutTenantScopeSetting); without == true {
+		return nil, nil, nil
+	}
+
+	tenant, ok := TenantOf(contextOf(scope.DB()))
+	if raw, _ := scope.Get(rawSetting); raw == true && ok {
+		return nil, nil, ErrTenantScopeBypass
+	}
+	column := taggedColumn(scope, TenantTag)
+	if column == nil {
+		// the records of a table given to Table may have a tenant column that the model does not tell of
+		if ok && tableOfOtherModel(scope) {
+			return nil, nil, ErrTenantScopeBypass
+		}
+		return nil, nil, nil
+	}
+	if unscoped, _ := scope.Get(unscopedSetting); unscoped == true {
+		return nil, nil, ErrTenantScopeBypass
+	}
+	if !ok {
+		return nil, nil, ErrNoTenant
+	}
+	return column, tenant, nil
+}
+
+// taggedColumn returns the field of the model of the scope with the jorm tag, nil if it has none
+func taggedColumn(scope *gorm.Scope, name string) *gorm.StructField {
+	if scope.Value == nil {
+		return nil
+	}
+	for _, field := range scope.GetModelStruct().StructFields {
+		for _, tag := range strings.Split(field.Tag.Get("jorm"), ";") {
+			if strings.TrimSpace(tag) == name {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+// tableOfOtherModel reports whether the statement runs on a table given to Table that is not the table of its model,
+// or on a table without a model
+func tableOfOtherModel(scope *gorm.Scope) bool {
+	return scope.TableName() != scope.DB().New().NewScope(scope.Value).TableName()
+}
+
+// sameTenant reports whether a tenant column value is the tenant, whatever their integer or pointer types
+func sameTenant(value, tenant interface{}) bool {
+	return fmt.Sprint(indirectValue(value)) == fmt.Sprint(indirectValue(tenant))
+}
+
+// indirectValue returns what a pointer points to
+func indirectValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// tenantExecError returns why an Exec on the db is refused, nil if it can run
+func tenantExecError(db *gorm.DB) error {
+	if without, _ := db.Get(withoutTenantScopeSetting); without == true {
+		return nil
+	}
+	if _, ok := TenantOf(contextOf(db)); ok {
+		return ErrTenantScopeBypass
+	}
+	return nil
+}
+
+var registerTenantCallbacksMu sync.Mutex
+
+// registerTenantCallbacks registers the gorm callbacks that restrict statements to the tenant of their context,
+// gorm shares callbacks between every db opened together so they are registered once
+func registerTenantCallbacks(db *gorm.DB) {
+	registerTenantCallbacksMu.Lock()
+	defer registerTenantCallbacksMu.Unlock()
+
+	callback := db.Callback()
+	if callback.Query().Get("jorm:tenant_query") != nil {
+		return
+	}
+
+	callback.Create().Before("gorm:before_create").Register("jorm:tenant_create", tenantCreate)
+	callback.Update().Before("gorm:before_update").Register("jorm:tenant_update", tenantUpdate)
+	callback.Delete().Before("gorm:before_delete").Register("jorm:tenant_delete", tenantWhere)
+	callback.Query().Before("gorm:query").Register("jorm:tenant_query", tenantWhere)
+	callback.RowQuery().Before("gorm:row_query").Register("jorm:tenant_row_query", tenantRowQuery)
+}
+
+// tenantCreate fills the tenant column of a record being created with the tenant of its context
+func tenantCreate(scope *gorm.Scope) {
+	column, tenant, err := tenantScope(scope)
+	if err == nil && column != nil {
+		err = setTenant(scope, column, tenant)
+	}
+	scope.Err(err)
+}
+
+// tenantUpdate restricts an update to the records of the tenant of its context and fills the tenant column of a
+// record being saved with it
+func tenantUpdate(scope *gorm.Scope) {
+	column, tenant, err := tenantScope(scope)
+	if err != nil || column == nil {
+		scope.Err(err)
+		return
+	}
+
+	addTenantCondition(scope, column, tenant)
+	if attrs, ok := scope.InstanceGet("gorm:update_attrs"); ok {
+		if value, ok := attrs.(map[string]interface{})[column.DBName]; ok && !sameTenant(value, tenant) {
+			scope.Err(ErrTenantMismatch)
+		}
+	} else if _, ok := scope.InstanceGet("gorm:update_interface"); !ok {
+		scope.Err(setTenant(scope, column, tenant))
+	}
+}
+
+// tenantWhere restricts a query or delete to the records of the tenant of its context
+func tenantWhere(scope *gorm.Scope) {
+	column, tenant, err := tenantScope(scope)
+	if err != nil || column == nil {
+		scope.Err(err)
+		return
+	}
+	addTenantCondition(scope, column, tenant)
+}
+
+// tenantRowQuery restricts a row query to the records of the tenant of its context
+func tenantRowQuery(scope *gorm.Scope) {
+	column, tenant, err := tenantScope(scope)
+	if err != nil {
+		refuseRowQuery(scope, err)
+	} else if column != nil {
+		addTenantCondition(scope, column, tenant)
+	}
+}
+
+// refuseRowQuery stops a row query from running and makes its row or rows hold the error, as gorm runs row queries
+// whatever the error of the db
+func refuseRowQuery(scope *gorm.Scope, err error) {
+	scope.SkipLeft()
+	result, _ := scope.InstanceGet("row_query_result")
+	switch result := result.(type) {
+	case *gorm.RowQueryResult:
+		result.Row = refusedDB.QueryRowContext(context.WithValue(context.Background(), refusalKey{}, err), "")
+	case *gorm.RowsQueryResult:
+		result.Error = err
+	}
+}
+
+// addTenantCondition adds the condition on the tenant column to a statement
+func addTenantCondition(scope *gorm.Scope, column *gorm.StructField, tenant interface{}) {
+	scope.Search.Where(fmt.Sprintf("%v.%v = ?", scope.QuotedTableName(), scope.Quote(column.DBName)), tenant)
+}
+
+// setTenant fills the blank tenant column of the record of a statement with the tenant, or returns
+// ErrTenantMismatch if the record has another tenant
+func setTenant(scope *gorm.Scope, column *gorm.StructField, tenant interface{}) error {
+	field, ok := scope.FieldByName(column.Name)
+	if !ok {
+		return nil
+	}
+	if field.IsBlank {
+		return scope.SetColumn(field, tenant)
+	}
+	if !sameTenant(field.Field.Interface(), tenant) {
+		return ErrTenantMismatch
+	}
+	return nil
+}
+
+// refusedDB is a connection pool that refuses every connection with the error of the context, so that a refused
+// row query returns a *sql.Row holding its error
+var refusedDB = sql.OpenDB(refusedConnector{})
+
+// refusalKey is the context key of the error refusedDB refuses connections with
+type refusalKey struct{}
+
+// refusedConnector opens the connections of refusedDB
+type refusedConnector struct{}
+
+// Connect returns the error of the context
+func (refusedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	err, _ := ctx.Value(refusalKey{}).(error)
+	if err == nil {
+		err = driver.ErrBadConn
+	}
+	return nil, err
+}
+
+// Driver returns the dry run driver, refusedDB never opens connections by name
+func (refusedConnector) Driver() driver.Driver {
+	return dryRunDriver{}
+}
@@ -0,0 +1,224 @@
+package jorm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jloom6/jorm"
+)
+
+type invoice struct {
+	ID       uint
+	TenantID uint `jorm:"tenant"`
+	Amount   int
+}
+
+// openTenants returns a db with the invoices table, three invoices of tenant 1 and one of tenant 2, and dbs whose
+// context has the tenants
+func openTenants(t *testing.T) (db, tenant1, tenant2 jorm.Interface) {
+	t.Helper()
+	db = open(t)
+	if err := db.AutoMigrate(&invoice{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	tenant1 = db.WithContext(jorm.WithTenant(context.Background(), uint(1)))
+	tenant2 = db.WithContext(jorm.WithTenant(context.Background(), 2))
+	for i := 0; i < 3; i++ {
+		if err := tenant1.Create(&invoice{Amount: i}).Error(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tenant2.Create(&invoice{Amount: 9}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return db, tenant1, tenant2
+}
+
+func TestTenantQueries(t *testing.T) {
+	db, tenant1, tenant2 := openTenants(t)
+
+	var invoices []invoice
+	if err := tenant1.Find(&invoices).Error(); err != nil || len(invoices) != 3 {
+		t.Fatalf("Find = %+v %v", invoices, err)
+	}
+	for _, inv := range invoices {
+		if inv.TenantID != 1 {
+			t.Errorf("Create filled the tenant column with %d", inv.TenantID)
+		}
+	}
+	var count int
+	if err := tenant2.Model(&invoice{}).Count(&count).Error(); err != nil || count != 1 {
+		t.Errorf("Count = %d %v", count, err)
+	}
+	var ids []uint
+	if err := tenant1.Model(&invoice{}).Pluck("id", &ids).Error(); err != nil || len(ids) != 3 {
+		t.Errorf("Pluck = %v %v", ids, err)
+	}
+	if err := tenant2.First(&invoice{}, invoices[0].ID).Error(); !errors.Is(err, jorm.ErrNotFound) {
+		t.Errorf("First of an invoice of another tenant = %v", err)
+	}
+
+	// models without a tenant column are not restricted
+	if err := tenant1.Find(&[]user{}).Error(); err != nil {
+		t.Errorf("Find of users = %v", err)
+	}
+	if err := tenant1.Unscoped().Find(&[]user{}).Error(); err != nil {
+		t.Errorf("Unscoped Find of users = %v", err)
+	}
+
+	if err := db.WithoutTenantScope().Model(&invoice{}).Count(&count).Error(); err != nil || count != 4 {
+		t.Errorf("Count without the tenant scope = %d %v", count, err)
+	}
+}
+
+func TestTenantWrites(t *testing.T) {
+	_, tenant1, tenant2 := openTenants(t)
+
+	if err := tenant2.Create(&invoice{TenantID: 1}).Error(); !errors.Is(err, jorm.ErrTenantMismatch) {
+		t.Errorf("Create for another tenant = %v", err)
+	}
+	if result := tenant2.Model(&invoice{}).Update("amount", 5); result.Error() != nil || result.RowsAffected() != 1 {
+		t.Errorf("Update = %d %v", result.RowsAffected(), result.Error())
+	}
+	if err := tenant2.Model(&invoice{}).Update("tenant_id", 1).Error(); !errors.Is(err, jorm.ErrTenantMismatch) {
+		t.Errorf("Update of the tenant column = %v", err)
+	}
+
+	var inv invoice
+	if err := tenant1.First(&inv).Error(); err != nil {
+		t.Fatal(err)
+	}
+	inv.Amount = 100
+	if err := tenant2.Save(&inv).Error(); !errors.Is(err, jorm.ErrTenantMismatch) {
+		t.Errorf("Save of an invoice of another tenant = %v", err)
+	}
+	if err := tenant1.Save(&inv).Error(); err != nil {
+		t.Errorf("Save = %v", err)
+	}
+
+	if result := tenant2.Delete(&invoice{}); result.Error() != nil || result.RowsAffected() != 1 {
+		t.Errorf("Delete = %d %v", result.RowsAffected(), result.Error())
+	}
+	var count int
+	if err := tenant1.Model(&invoice{}).Count(&count).Error(); err != nil || count != 3 {
+		t.Errorf("Count after Delete of another tenant = %d %v", count, err)
+	}
+}
+
+func TestTenantRequired(t *testing.T) {
+	db, _, _ := openTenants(t)
+
+	if err := db.Find(&[]invoice{}).Error(); !errors.Is(err, jorm.ErrNoTenant) {
+		t.Errorf("Find = %v", err)
+	}
+	var count int
+	if err := db.Model(&invoice{}).Count(&count).Error(); !errors.Is(err, jorm.ErrNoTenant) {
+		t.Errorf("Count = %v", err)
+	}
+	var ids []uint
+	if err := db.Model(&invoice{}).Pluck("id", &ids).Error(); !errors.Is(err, jorm.ErrNoTenant) {
+		t.Errorf("Pluck = %v", err)
+	}
+	// Raw and Exec without a tenant are not restricted
+	if err := db.Exec("SELECT 1").Error(); err != nil {
+		t.Errorf("Exec = %v", err)
+	}
+}
+
+func TestTenantScopeBypass(t *testing.T) {
+	_, tenant1, _ := openTenants(t)
+
+	if err := tenant1.Exec("DELETE FROM invoices").Error(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Exec = %v", err)
+	}
+	if err := tenant1.Raw("SELECT * FROM invoices").Scan(&[]invoice{}).Error(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Raw Scan = %v", err)
+	}
+	var count int
+	if err := tenant1.Raw("SELECT count(*) FROM invoices").Row().Scan(&count); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Raw Row = %v", err)
+	}
+	if _, err := tenant1.Raw("SELECT * FROM invoices").Rows(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Raw Rows = %v", err)
+	}
+	if err := tenant1.Unscoped().Find(&[]invoice{}).Error(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Unscoped Find = %v", err)
+	}
+
+	if err := tenant1.WithoutTenantScope().Raw("SELECT count(*) FROM invoices").Row().Scan(&count); err != nil || count != 4 {
+		t.Errorf("Raw without the tenant scope = %d %v", count, err)
+	}
+}
+
+func TestTenantTable(t *testing.T) {
+	db, tenant1, _ := openTenants(t)
+
+	if err := tenant1.Table("invoices").Where("amount >= ?", 0).UpdateColumn("amount", 100).Error(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Table UpdateColumn = %v", err)
+	}
+	if err := tenant1.Table("invoices").Where("amount >= ?", 0).Delete(nil).Error(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Table Delete = %v", err)
+	}
+	var count int
+	if err := tenant1.Table("invoices").Count(&count).Error(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Table Count = %v", err)
+	}
+	// a model without a tenant column does not tell of the tenant column of another table
+	if err := tenant1.Table("invoices").Find(&[]user{}).Error(); !errors.Is(err, jorm.ErrTenantScopeBypass) {
+		t.Errorf("Table Find into users = %v", err)
+	}
+	if err := db.WithoutTenantScope().Model(&invoice{}).Where("amount = ?", 100).Count(&count).Error(); err != nil || count != 0 {
+		t.Errorf("invoices updated to 100 = %d %v", count, err)
+	}
+
+	// the table of the model is scoped as the model is
+	if err := tenant1.Table("invoices").Model(&invoice{}).Count(&count).Error(); err != nil || count != 3 {
+		t.Errorf("Table Count of the model = %d %v", count, err)
+	}
+	if err := tenant1.Table("users").Find(&[]user{}).Error(); err != nil {
+		t.Errorf("Table Find of the table of the model = %v", err)
+	}
+	if err := tenant1.WithoutTenantScope().Table("invoices").Count(&count).Error(); err != nil || count != 4 {
+		t.Errorf("Table Count without the tenant scope = %d %v", count, err)
+	}
+	// without a tenant a Table statement is not restricted, like Raw
+	if err := db.Table("invoices").Count(&count).Error(); err != nil || count != 4 {
+		t.Errorf("Table Count without a tenant = %d %v", count, err)
+	}
+}
+
+func TestTenantTransaction(t *testing.T) {
+	_, tenant1, _ := openTenants(t)
+
+	// the transaction's statements have the tenant of its context
+	err := tenant1.Transaction(context.Background(), func(tx jorm.Interface) error {
+		return tx.Create(&invoice{}).Error()
+	})
+	if !errors.Is(err, jorm.ErrNoTenant) {
+		t.Errorf("Transaction without a tenant = %v", err)
+	}
+
+	err = tenant1.Transaction(jorm.WithTenant(context.Background(), uint(1)), func(tx jorm.Interface) error {
+		var invoices []invoice
+		if err := tx.Find(&invoices).Error(); err != nil {
+			return err
+		}
+		if len(invoices) != 3 {
+			t.Errorf("Find in a transaction = %+v", invoices)
+		}
+		return tx.Save(&invoices[0]).Error()
+	})
+	if err != nil {
+		t.Errorf("Transaction = %v", err)
+	}
+}
+
+func TestTenantOf(t *testing.T) {
+	if tenant, ok := jorm.TenantOf(context.Background()); ok || tenant != nil {
+		t.Errorf("TenantOf without a tenant = %v %v", tenant, ok)
+	}
+	if tenant, ok := jorm.TenantOf(jorm.WithTenant(context.Background(), "acme")); !ok || tenant != "acme" {
+		t.Errorf("TenantOf = %v %v", tenant, ok)
+	}
+}
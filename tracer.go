@@ -0,0 +1,75 @@
+package jorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/smacker/opentracing-gorm"
+)
+
+// gorm setting and statement hook name of the tracer set with WithTracer
+const (
+	tracerSetting  = "jorm:tracer"
+	tracerHookName = "jorm:tracer"
+)
+
+// Tracer traces the statements of a db, OpenTracing is used unless another one is set with WithTracer
+type Tracer interface {
+	// WithContext returns the gorm db used by WithContext, for tracers that keep the span of the context in it
+	WithContext(ctx context.Context, db *gorm.DB) *gorm.DB
+	// TraceStatement is called after every statement of the db, the span of the statement's context is its parent
+	TraceStatement(stmt *Statement)
+	// TraceID returns the ID of the trace of the span of the context, or an empty string if there is none
+	TraceID(ctx context.Context) string
+}
+
+// WithTracer returns a clone of the db whose statements are traced by the given tracer instead of the current one
+//     db = db.WithTracer(jormotel.New())
+func (db *DB) WithTracer(tracer Tracer) *DB {
+	c := db.OnStatement(tracerHookName, tracer.TraceStatement)
+	c.db = c.db.Set(tracerSetting, tracer)
+	return c
+}
+
+// tracerOf returns the tracer set to the db with WithTracer, OpenTracing if there is none
+func tracerOf(db *gorm.DB) Tracer {
+	if tracer, ok := db.Get(tracerSetting); ok {
+		return tracer.(Tracer)
+	}
+	return OpenTracing()
+}
+
+// openTracing traces statements with the callbacks of opentracing-gorm
+type openTracing struct{}
+
+// OpenTracing returns the Tracer jorm uses by default, it sets the span of the context to the db so the callbacks
+// registered with otgorm.AddGormCallbacks create the spans of statements
+func OpenTracing() Tracer {
+	return openTracing{}
+}
+
+// WithContext sets the span of the context to the db
+func (openTracing) WithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return otgorm.SetSpanToGorm(ctx, db)
+}
+
+// TraceStatement does nothing as the spans are created by the opentracing-gorm callbacks
+func (openTracing) TraceStatement(stmt *Statement) {}
+
+// TraceID returns the trace ID of the span of the context, OpenTracing leaves trace IDs to the tracer so it is only
+// found if the span context has a TraceID method as the Jaeger and Zipkin ones do
+func (openTracing) TraceID(ctx context.Context) string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+
+	method := reflect.ValueOf(span.Context()).MethodByName("TraceID")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return ""
+	}
+	return fmt.Sprint(method.Call(nil)[0].Interface())
+}
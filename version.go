@@ -0,0 +1,130 @@
+package jorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jinzhu/gorm"
+)
+
+// VersionTag is the tag of the version field of a model, an integer that is incremented by every update so that
+// Save, Update, Updates and Delete of a record fail with ErrStaleObject if it changed since it was read
+//     type Account struct {
+//         ID      uint
+//         Balance int
+//         Version int `jorm:"version"`
+//     }
+const VersionTag = "version"
+
+// versionInstanceKey is the gorm instance setting the version a statement expects is stored under
+const versionInstanceKey = "jorm:version"
+
+// ErrStaleObject is returned by Save, Update, Updates and Delete of a record with a version field when no row has
+// the version of the record, because another statement updated or deleted it since it was read
+var ErrStaleObject = errors.New("jorm: stale object")
+
+var registerVersionCallbacksMu sync.Mutex
+
+// registerVersionCallbacks registers the gorm callbacks that lock records on their version,
+// gorm shares callbacks between every db opened together so they are registered once
+func registerVersionCallbacks(db *gorm.DB) {
+	registerVersionCallbacksMu.Lock()
+	defer registerVersionCallbacksMu.Unlock()
+
+	callback := db.Callback()
+	if callback.Update().Get("jorm:lock_version") != nil {
+		return
+	}
+
+	callback.Update().Before("gorm:update").Register("jorm:lock_version", lockVersion(true))
+	callback.Update().After("gorm:update").Register("jorm:check_version", checkVersion(true))
+	callback.Delete().Before("gorm:delete").Register("jorm:lock_version", lockVersion(false))
+	callback.Delete().After("gorm:delete").Register("jorm:check_version", checkVersion(false))
+}
+
+// lockVersion restricts an update or delete of a record to its version and increments the version of updated rows,
+// UpdateColumn and UpdateColumns leave the version as they leave the other columns they do not update
+func lockVersion(update bool) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		if _, ok := scope.Get("gorm:update_column"); ok || scope.HasError() {
+			return
+		}
+		if column := versionColumn(scope); column != nil {
+			lockRecordVersion(scope, column, update)
+		}
+	}
+}
+
+// lockRecordVersion increments the version of updated rows and restricts the update or delete of a record to its
+// version
+func lockRecordVersion(scope *gorm.Scope, column *gorm.StructField, update bool) {
+	attrs, updateAttrs := scope.InstanceGet("gorm:update_attrs")
+	if updateAttrs {
+		attrs.(map[string]interface{})[column.DBName] = gorm.Expr(fmt.Sprintf("%v + 1", scope.Quote(column.DBName)))
+	}
+
+	// only the update or delete of a record is locked, those of every record matching conditions are not
+	if scope.IndirectValue().Kind() != reflect.Struct || scope.PrimaryKeyZero() {
+		return
+	}
+	field, ok := scope.FieldByName(column.Name)
+	if !ok {
+		return
+	}
+	version := field.Field.Interface()
+	scope.Search.Where(fmt.Sprintf("%v.%v = ?", scope.QuotedTableName(), scope.Quote(column.DBName)), version)
+	scope.InstanceSet(versionInstanceKey, version)
+	if update && !updateAttrs {
+		// Save writes every field so the record holds the next version
+		field.Set(nextVersion(field.Field))
+	}
+}
+
+// checkVersion returns ErrStaleObject if a locked update or delete changed no row, and sets the version of the
+// record to the version it was updated to, or back to the version it had if the update failed
+func checkVersion(update bool) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		version, ok := scope.InstanceGet(versionInstanceKey)
+		if !ok {
+			return
+		}
+		field, _ := scope.FieldByName(versionColumn(scope).Name)
+
+		if !scope.HasError() && scope.DB().RowsAffected == 0 {
+			scope.Err(ErrStaleObject)
+		}
+		if scope.HasError() {
+			field.Set(version)
+		} else if update {
+			field.Set(nextVersion(reflect.ValueOf(version)))
+		}
+	}
+}
+
+// versionColumn returns the version field of the model of the scope, nil if it has none or it is not an integer
+func versionColumn(scope *gorm.Scope) *gorm.StructField {
+	column := taggedColumn(scope, VersionTag)
+	if column == nil {
+		return nil
+	}
+	switch column.Struct.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return column
+	}
+	return nil
+}
+
+// nextVersion returns the version after the integer version
+func nextVersion(version reflect.Value) interface{} {
+	next := reflect.New(version.Type()).Elem()
+	switch version.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		next.SetInt(version.Int() + 1)
+	default:
+		next.SetUint(version.Uint() + 1)
+	}
+	return next.Interface()
+}
@@ -0,0 +1,141 @@
+package jorm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jloom6/jorm"
+)
+
+type account struct {
+	ID      uint
+	Balance int
+	Version int `jorm:"version"`
+}
+
+type archivedAccount struct {
+	ID        uint
+	Version   uint `jorm:"version"`
+	DeletedAt *time.Time
+}
+
+// openAccounts returns a db with the accounts table and an account with a balance of 10
+func openAccounts(t *testing.T) (jorm.Interface, account) {
+	t.Helper()
+	db := open(t)
+	if err := db.AutoMigrate(&account{}, &archivedAccount{}).Error(); err != nil {
+		t.Fatal(err)
+	}
+	a := account{Balance: 10}
+	if err := db.Create(&a).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return db, a
+}
+
+// reload returns the account as it is in the database
+func reload(t *testing.T, db jorm.Interface, id uint) account {
+	t.Helper()
+	var a account
+	if err := db.First(&a, id).Error(); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestVersionSave(t *testing.T) {
+	db, a := openAccounts(t)
+	stale := reload(t, db, a.ID)
+
+	a.Balance = 20
+	if err := db.Save(&a).Error(); err != nil || a.Version != 1 {
+		t.Fatalf("Save = %+v %v", a, err)
+	}
+	stale.Balance = 30
+	if err := db.Save(&stale).Error(); !errors.Is(err, jorm.ErrStaleObject) || stale.Version != 0 {
+		t.Errorf("Save of a stale account = %+v %v", stale, err)
+	}
+
+	var count int
+	if err := db.Model(&account{}).Count(&count).Error(); err != nil || count != 1 {
+		t.Errorf("Save of a stale account created a record, count = %d %v", count, err)
+	}
+	if got := reload(t, db, a.ID); got.Balance != 20 || got.Version != 1 {
+		t.Errorf("account = %+v", got)
+	}
+}
+
+func TestVersionUpdate(t *testing.T) {
+	db, a := openAccounts(t)
+	stale := reload(t, db, a.ID)
+
+	if err := db.Model(&a).Updates(map[string]interface{}{"balance": 50}).Error(); err != nil || a.Version != 1 || a.Balance != 50 {
+		t.Fatalf("Updates = %+v %v", a, err)
+	}
+	if err := db.Model(&stale).Update("balance", 40).Error(); !errors.Is(err, jorm.ErrStaleObject) {
+		t.Errorf("Update of a stale account = %v", err)
+	}
+	if got := reload(t, db, a.ID); got.Balance != 50 || got.Version != 1 {
+		t.Errorf("account = %+v", got)
+	}
+
+	// updates of every record matching conditions are not locked but still increment the version
+	if err := db.Model(&account{}).Where("balance > ?", 0).Update("balance", 60).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(t, db, a.ID); got.Balance != 60 || got.Version != 2 {
+		t.Errorf("account after a batch update = %+v", got)
+	}
+
+	// UpdateColumn leaves the version alone
+	if err := db.Model(&a).UpdateColumn("balance", 70).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(t, db, a.ID); got.Balance != 70 || got.Version != 2 {
+		t.Errorf("account after UpdateColumn = %+v", got)
+	}
+}
+
+func TestVersionDelete(t *testing.T) {
+	db, a := openAccounts(t)
+	stale := reload(t, db, a.ID)
+
+	a.Balance = 20
+	if err := db.Save(&a).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(&stale).Error(); !errors.Is(err, jorm.ErrStaleObject) {
+		t.Errorf("Delete of a stale account = %v", err)
+	}
+	if err := db.Delete(&a).Error(); err != nil {
+		t.Errorf("Delete = %v", err)
+	}
+
+	// a soft deleted record is gone for the next delete
+	archived := archivedAccount{}
+	if err := db.Create(&archived).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(&archived).Error(); err != nil {
+		t.Fatalf("soft Delete = %v", err)
+	}
+	if err := db.Delete(&archived).Error(); !errors.Is(err, jorm.ErrStaleObject) {
+		t.Errorf("soft Delete of a deleted record = %v", err)
+	}
+}
+
+func TestVersionStatement(t *testing.T) {
+	db, _ := openAccounts(t)
+
+	sql, vars, err := db.(*jorm.DB).ToSQL(func(tx jorm.Interface) jorm.Interface {
+		return tx.Model(&account{ID: 5, Version: 7}).Update("balance", 1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `UPDATE "accounts" SET "balance" = ?, "version" = "version" + 1  WHERE "accounts"."id" = ? AND (("accounts"."version" = ?))`
+	if sql != want || len(vars) != 3 {
+		t.Errorf("ToSQL = %q %v", sql, vars)
+	}
+}